@@ -0,0 +1,41 @@
+// Команда locale-report проверяет файлы переводов по правилам (см. i18n.Validate)
+// и показывает, насколько они полны: каких строк не хватает, какие лишние, пустые
+// или совпадают с английскими.
+//
+//	go run ./backend/cmd/locale-report [-dir locales] [-strict] [код ...]
+//
+// Без кодов проверяются все языки, кроме английского.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"lce/backend/modules/i18n"
+)
+
+func main() {
+	dir := flag.String("dir", "locales", "папка с файлами переводов")
+	strict := flag.Bool("strict", false, "завершиться с ошибкой, если файлы нарушают правила или в переводах не хватает строк, есть лишние или пустые")
+	flag.Parse()
+
+	problems := i18n.Validate(os.DirFS(*dir))
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, p)
+	}
+
+	reports, err := i18n.Reports(os.DirFS(*dir), flag.Args()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	incomplete := len(problems) > 0
+	for _, r := range reports {
+		fmt.Println(r)
+		incomplete = incomplete || !r.Complete()
+	}
+	if *strict && incomplete {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,26 @@
+// Команда settings-types создаёт объявления типов TypeScript для настроек приложения
+// по реестру полей app_settings. Запускается задачей common:generate:settings-types.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"lce/backend/modules/app_settings"
+)
+
+func main() {
+	out := flag.String("o", "frontend/src/types/settings.d.ts", "файл для записи объявлений")
+	flag.Parse()
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "не удалось создать папку для %s: %v\n", *out, err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, []byte(app_settings.TypeScriptDefinitions()), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "не удалось записать %s: %v\n", *out, err)
+		os.Exit(1)
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sync" // Для обеспечения потокобезопасности при изменении настроек
 
-	"github.com/wailsapp/wails/v3/pkg/application" // <--- ДОБАВЛЯЕМ ИМПОРТ WAILS APPLICATION
+	"lce/backend/modules/config_watcher"
+	"lce/backend/modules/data_dir"
+	"lce/backend/modules/paths_scanner"
 )
 
 // Settings - структура для хранения настроек приложения
 type Settings struct {
+	Version int `json:"version"` // Версия формата файла, см. CurrentSettingsVersion
+
 	Width    uint32   `json:"width"`
 	Height   uint32   `json:"height"`
 	Language string   `json:"language"`
@@ -19,39 +22,66 @@ type Settings struct {
 	FirstRun bool     `json:"first_run"` // NEW: Добавляем поле FirstRun
 	AllPaths []string `json:"all_paths"` // NEW: Добавляем поле AllPaths
 	Theme    string   `json:"theme"`     // NEW: Добавляем поле Theme
+
+	ScanRules paths_scanner.ScanRules `json:"scan_rules"` // Правила поиска папок с игрой
+
+	Installs        []Install `json:"installs"`          // Известные установки игры
+	ActiveInstallID string    `json:"active_install_id"` // Активная установка; GamePath и AllPaths повторяют Installs
+
+	WatchMode           config_watcher.Mode `json:"watch_mode"`             // auto, native или poll
+	WatchPollIntervalMs int                 `json:"watch_poll_interval_ms"` // интервал опроса файлов в режиме poll
+
+	Windows map[string]WindowState `json:"windows"` // положение и размер окон по именам (MainWindow, SettingsWindow)
 }
 
 // DefaultSettings возвращает настройки по умолчанию
 func DefaultSettings() Settings {
 	return Settings{
+		Version:  CurrentSettingsVersion,
 		Width:    1600,
 		Height:   900,
-		Language: "en",
+		Language: "en", // при первом запуске заменяется языком системы (i18n.UseSystemLanguage)
 		GamePath: "",
 		FirstRun: true,       // NEW: Значение по умолчанию для FirstRun
 		AllPaths: []string{}, // NEW: Значение по умолчанию для AllPaths
 		Theme:    "default",  // NEW: Значение по умолчанию для Theme
+
+		ScanRules: paths_scanner.DefaultScanRules(),
+
+		Installs: []Install{},
+
+		WatchMode:           config_watcher.ModeAuto,
+		WatchPollIntervalMs: 2000,
+
+		Windows: map[string]WindowState{},
 	}
 }
 
+// GetConfigDir возвращает директорию данных приложения, создавая её при необходимости.
+// Там хранятся settings.json и служебные файлы (например, кэш сканирования).
+// В переносном режиме это папка рядом с программой (см. data_dir).
+func GetConfigDir() (string, error) {
+	return data_dir.Dir(data_dir.Data)
+}
+
 // getSettingsPath возвращает путь к файлу настроек
 func getSettingsPath() (string, error) {
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		return "", fmt.Errorf("не удалось получить директорию конфигурации пользователя: %w", err)
-	}
+	return SettingsPath()
+}
 
-	appConfigDir := filepath.Join(configDir, "LoD Config Editor")
-	if _, err := os.Stat(appConfigDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(appConfigDir, 0755); err != nil {
-			return "", fmt.Errorf("не удалось создать директорию конфигурации приложения: %w", err)
-		}
+// SettingsPath возвращает путь к settings.json.
+func SettingsPath() (string, error) {
+	appConfigDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
 	}
-
 	return filepath.Join(appConfigDir, "settings.json"), nil
 }
 
-// LoadSettings загружает настройки из файла
+// LoadSettings загружает настройки из файла.
+// Старые файлы последовательно обновляются миграциями (см. settingsMigrations), исходный файл
+// при этом сохраняется в settings.json.bak. Нечитаемый файл не перезаписывается без копии:
+// он сохраняется как settings.json.broken-<время>, а приложение запускается с настройками по умолчанию.
 func LoadSettings() (Settings, error) {
 	settingsPath, err := getSettingsPath()
 	if err != nil {
@@ -68,52 +98,50 @@ func LoadSettings() (Settings, error) {
 			}
 			return defaultSettings, nil
 		}
+		// Файл есть, но не читается (нет доступа, заблокирован) - не трогаем его
 		return DefaultSettings(), fmt.Errorf("не удалось прочитать файл настроек: %w", err)
 	}
 
-	var userSettings Settings
-	// Инициализируем userSettings значениями по умолчанию, чтобы отсутствующие поля сохраняли их
-	defaultValues := DefaultSettings()
-	userSettings = defaultValues
-
-	if err := json.Unmarshal(data, &userSettings); err != nil {
-		// Ошибка десериализации, используем настройки по умолчанию и логируем ошибку
-		fmt.Printf("Ошибка десериализации настроек, используются настройки по умолчанию: %v\n", err)
-		// Пытаемся сохранить настройки по умолчанию, если десериализация не удалась, чтобы файл был валидным
+	userSettings, raw, err := decodeSettings(data)
+	if err != nil {
+		defaultValues := DefaultSettings()
+		brokenPath, backupErr := preserveBrokenSettings(settingsPath, data)
+		if backupErr != nil {
+			// Без копии файл не перезаписываем: пусть пользователь исправит его сам
+			return defaultValues, fmt.Errorf("ошибка десериализации настроек (%v), файл оставлен без изменений: %w", err, backupErr)
+		}
+		fmt.Printf("Ошибка десериализации настроек, файл сохранён как %s, используются настройки по умолчанию: %v\n", brokenPath, err)
 		if err := SaveSettings(&defaultValues); err != nil {
 			fmt.Printf("Не удалось сохранить настройки по умолчанию после ошибки десериализации: %v\n", err)
 		}
-		return defaultValues, fmt.Errorf("ошибка десериализации: %w", err)
+		return defaultValues, fmt.Errorf("ошибка десериализации, повреждённый файл сохранён как %s: %w", brokenPath, err)
 	}
 
-	// Минимальные размеры
-	if userSettings.Width < 650 {
-		userSettings.Width = 650
-	}
-	if userSettings.Height < 350 {
-		userSettings.Height = 350
+	if userSettings.Version > CurrentSettingsVersion {
+		// Файл записан более новой версией приложения: читаем что понимаем и не перезаписываем его
+		fmt.Printf("Файл настроек имеет версию %d, приложение поддерживает %d; настройки не будут обновлены\n",
+			userSettings.Version, CurrentSettingsVersion)
+		userSettings.normalize()
+		return userSettings, nil
 	}
 
-	// Проверяем и добавляем отсутствующие ключи (если структура изменилась или были пустые значения)
-	updated := false
-	if userSettings.Language == "" {
-		userSettings.Language = defaultValues.Language
-		updated = true
-	}
-	if userSettings.GamePath == "" {
-		userSettings.GamePath = defaultValues.GamePath
-		updated = true
-	}
-	if userSettings.AllPaths == nil { // Проверяем на nil, если поле отсутствовало в старых настройках
-		userSettings.AllPaths = defaultValues.AllPaths
-		updated = true
-	}
-	if userSettings.Theme == "" { // Проверяем на пустую строку, если поле отсутствовало
-		userSettings.Theme = defaultValues.Theme
-		updated = true
+	fromVersion := userSettings.Version
+	applied := migrateSettings(&userSettings, raw)
+	normalized := userSettings.normalize()
+
+	if len(applied) > 0 {
+		backupPath, err := backupSettingsFile(settingsPath, data)
+		if err != nil {
+			// Без резервной копии не перезаписываем файл: миграции применены только в памяти
+			return userSettings, err
+		}
+		fmt.Printf("Настройки обновлены с версии %d до %d (копия: %s):\n", fromVersion, userSettings.Version, backupPath)
+		for _, m := range applied {
+			fmt.Printf("  %s\n", m)
+		}
 	}
 
-	if updated {
+	if len(applied) > 0 || normalized {
 		if err := SaveSettings(&userSettings); err != nil {
 			return userSettings, fmt.Errorf("не удалось сохранить обновленные настройки: %w", err)
 		}
@@ -122,170 +150,111 @@ func LoadSettings() (Settings, error) {
 	return userSettings, nil
 }
 
-// SaveSettings сохраняет настройки в файл
+// SaveSettings сохраняет настройки в файл.
+// Запись идёт через временный файл, чтобы сбой посреди записи не оставил повреждённый settings.json.
 func SaveSettings(newSettings *Settings) error {
 	settingsPath, err := getSettingsPath()
 	if err != nil {
 		return err
 	}
 
+	if newSettings.Version < CurrentSettingsVersion {
+		newSettings.Version = CurrentSettingsVersion
+	}
 	jsonBytes, err := json.MarshalIndent(newSettings, "", "    ")
 	if err != nil {
 		return fmt.Errorf("не удалось сериализовать настройки в JSON: %w", err)
 	}
 
-	if err := os.WriteFile(settingsPath, jsonBytes, 0644); err != nil {
+	tmpPath := settingsPath + ".tmp"
+	if err := os.WriteFile(tmpPath, jsonBytes, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл настроек: %w", err)
+	}
+	if err := os.Rename(tmpPath, settingsPath); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("не удалось записать файл настроек: %w", err)
 	}
 	return nil
 }
 
 // AppSettings - это структура, которая будет привязана к фронтенду Wails.
-// Она содержит текущие настройки и мьютекс для потокобезопасного доступа.
+// Она открывает фронтенду хранилище настроек Store, общее для всех сервисов.
 type AppSettings struct {
-	app      *application.App // <--- ДОБАВЛЕНО: Ссылка на приложение Wails
-	settings Settings
-	lock     sync.RWMutex // Мьютекс для безопасного доступа к настройкам
+	store *Store
 }
 
-// NewAppSettings создает новый экземпляр AppSettings.
-// Он загружает существующие настройки или использует значения по умолчанию.
-func NewAppSettings(app *application.App) *AppSettings { // <--- ИЗМЕНЕНО: Принимает *application.App
-	s, err := LoadSettings()
-	if err != nil {
-		fmt.Printf("Ошибка при загрузке настроек: %v. Используются настройки по умолчанию.\n", err)
-		s = DefaultSettings()
-	}
-	return &AppSettings{
-		app:      app, // <--- ИНИЦИАЛИЗАЦИЯ
-		settings: s,
-	}
+// NewAppSettings создает сервис настроек поверх хранилища store.
+func NewAppSettings(store *Store) *AppSettings {
+	return &AppSettings{store: store}
 }
 
 // GetSettings возвращает текущие настройки приложения.
 // Эта функция привязана к фронтенду Wails.
 func (a *AppSettings) GetSettings() Settings {
-	a.lock.RLock()
-	defer a.lock.RUnlock()
-	return a.settings
+	return a.store.Get()
 }
 
 // UpdateSettings обновляет настройки приложения на основе предоставленной карты.
+// Значения проверяются по реестру settingsFields; если хотя бы одно некорректно,
+// ничего не сохраняется и возвращается *ValidationError с ошибками по полям.
 // Эта функция привязана к фронтенду Wails.
 func (a *AppSettings) UpdateSettings(newSettings map[string]interface{}) (Settings, error) {
-	fmt.Println("=== Начало UpdateSettings ===")
-	fmt.Printf("Входные данные: %+v\n", newSettings)
-
-	a.lock.Lock()
-	defer a.lock.Unlock()
-
-	currentSettings := a.settings // Создаем копию для работы
-
-	fmt.Printf("Текущие настройки перед обновлением: %+v\n", currentSettings)
-
-	updated := false
-	for key, value := range newSettings {
-		fmt.Printf("Обработка поля '%s': %+v\n", key, value)
-		switch key {
-		case "width":
-			if v, ok := value.(float64); ok { // Числа из JSON - это float64 в Go
-				currentSettings.Width = uint32(v)
-				updated = true
-			}
-		case "height":
-			if v, ok := value.(float64); ok {
-				currentSettings.Height = uint32(v)
-				updated = true
-			}
-		case "language":
-			if v, ok := value.(string); ok {
-				currentSettings.Language = v
-				updated = true
-			}
-		case "game_path":
-			if v, ok := value.(string); ok {
-				currentSettings.GamePath = v
-				updated = true
-			}
-		case "first_run": // NEW: Добавляем обработку для first_run
-			if v, ok := value.(bool); ok {
-				currentSettings.FirstRun = v
-				updated = true
-			}
-		case "all_paths": // NEW: Добавляем обработку для all_paths
-			if v, ok := value.([]interface{}); ok { // JSON массив будет []interface{}
-				var paths []string
-				for _, item := range v {
-					if pathStr, ok := item.(string); ok {
-						paths = append(paths, pathStr)
-					}
-				}
-				currentSettings.AllPaths = paths
-				updated = true
-			}
-		case "theme": // NEW: Добавляем обработку для theme
-			if v, ok := value.(string); ok {
-				currentSettings.Theme = v
-				updated = true
-			}
-		default:
-			fmt.Printf("Неизвестное поле: %s\n", key)
-		}
-	}
-
-	// Повторная проверка минимальных размеров после обновлений
-	if currentSettings.Width < 650 {
-		currentSettings.Width = 650
-		updated = true
-	}
-	if currentSettings.Height < 350 {
-		currentSettings.Height = 350
-		updated = true
-	}
-
-	if updated {
-		if err := SaveSettings(&currentSettings); err != nil {
-			fmt.Printf("Ошибка сохранения: %v\n", err)
-			return a.settings, fmt.Errorf("не удалось сохранить настройки: %w", err) // Возвращаем старые настройки в случае ошибки
-		}
-		a.settings = currentSettings // Обновляем внутренние настройки только если сохранение было успешным
-		fmt.Println("Настройки успешно сохранены")
-
-		// <--- ДОБАВЛЕНА ОТЛАДОЧНАЯ ПЕЧАТЬ ---
-		fmt.Printf("Debug: Emitting event 'app-settings-updated' with data. Type: %T, Value: %+v\n", a.settings, a.settings)
-		// --- КОНЕЦ ОТЛАДОЧНОЙ ПЕЧАТИ ---
-
-		a.app.Event.Emit("app-settings-updated", a.settings)
-	} else {
-		fmt.Println("Настройки не изменились, сохранение не требуется.")
+	settings, err := a.store.UpdateFields(newSettings)
+	if err != nil {
+		fmt.Printf("Настройки не обновлены: %v\n", err)
 	}
+	return settings, err
+}
 
-	fmt.Println("=== Конец UpdateSettings ===")
-	return a.settings, nil
+// ReloadSettings перечитывает settings.json (например, после правки вручную).
+// Если настройки на диске отличаются от текущих, они применяются и фронтенд уведомляется.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) ReloadSettings() (Settings, error) {
+	return a.store.Reload()
 }
 
-// GetOption возвращает значение определенной опции по ключу.
+// GetOption возвращает значение определенной опции по ключу или nil для неизвестного ключа.
 // Эта функция привязана к фронтенду Wails.
 func (a *AppSettings) GetOption(key string) interface{} {
-	a.lock.RLock()
-	defer a.lock.RUnlock()
-
-	switch key {
-	case "width":
-		return a.settings.Width
-	case "height":
-		return a.settings.Height
-	case "language":
-		return a.settings.Language
-	case "game_path":
-		return a.settings.GamePath
-	case "first_run": // NEW: Добавляем возврат значения для first_run
-		return a.settings.FirstRun
-	case "all_paths": // NEW: Добавляем возврат значения для all_paths
-		return a.settings.AllPaths
-	case "theme": // NEW: Добавляем возврат значения для theme
-		return a.settings.Theme
-	default:
+	f, ok := fieldsByName[key]
+	if !ok {
 		return nil
 	}
+	settings := a.store.Get()
+	return f.get(&settings)
+}
+
+// GetDataLayout возвращает, где приложение хранит файлы и включён ли переносной режим.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) GetDataLayout() data_dir.Layout {
+	return data_dir.Current()
+}
+
+// GetScanRules возвращает текущие правила сканирования.
+// Используется сканером путей и привязана к фронтенду Wails.
+func (a *AppSettings) GetScanRules() paths_scanner.ScanRules {
+	return a.store.Get().ScanRules
+}
+
+// decodeScanRules преобразует значение из фронтенда в правила сканирования.
+// Wails передаёт объект JSON как map[string]any, поэтому он проходит через json ещё раз:
+// так поля сопоставляются по тегам ScanRules, а неверные типы значений дают ошибку.
+func decodeScanRules(value interface{}) (paths_scanner.ScanRules, error) {
+	rules, ok := value.(paths_scanner.ScanRules)
+	if !ok {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return rules, fmt.Errorf("некорректные правила сканирования: %w", err)
+		}
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return rules, fmt.Errorf("некорректные правила сканирования: %w", err)
+		}
+	}
+	if err := rules.Validate(); err != nil {
+		return rules, fmt.Errorf("некорректные правила сканирования: %w", err)
+	}
+	if rules.Roots == nil {
+		rules.Roots = []string{}
+	}
+	return rules, nil
 }
@@ -0,0 +1,468 @@
+package app_settings
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"lce/backend/modules/config_watcher"
+	"lce/backend/modules/paths_scanner"
+)
+
+// FieldType - тип значения настройки в JSON, как его видит фронтенд.
+type FieldType string
+
+const (
+	FieldNumber     FieldType = "number"
+	FieldString     FieldType = "string"
+	FieldBool       FieldType = "bool"
+	FieldStringList FieldType = "string_list"
+	FieldObject     FieldType = "object"
+)
+
+// Field - описание одной настройки: имя в settings.json, тип, значение по умолчанию и правила изменения.
+// По реестру settingsFields работают UpdateSettings, GetOption, проверка значений и генерация типов TypeScript.
+type Field struct {
+	Name            string    `json:"name"`
+	Type            FieldType `json:"type"`
+	Default         any       `json:"default"`
+	RequiresRestart bool      `json:"requires_restart"` // новое значение применяется после перезапуска
+	ReadOnly        bool      `json:"read_only"`        // меняется только отдельными методами, не через UpdateSettings
+
+	tsType string
+	get    func(s *Settings) any
+	set    func(s *Settings, value any) error
+}
+
+// fieldSpec описывает настройку типа T. build превращает описание в Field с замыканиями
+// для чтения и записи, так что реестр обходится без reflect.
+type fieldSpec[T any] struct {
+	name     string
+	typ      FieldType
+	tsType   string
+	ptr      func(s *Settings) *T
+	decode   func(value any) (T, error)
+	validate func(v T) error
+	assign   func(s *Settings, v T) // если записи в поле недостаточно (например, нужно обновить установки)
+	restart  bool
+	readOnly bool
+}
+
+func (f fieldSpec[T]) build() Field {
+	defaults := DefaultSettings()
+	return Field{
+		Name:            f.name,
+		Type:            f.typ,
+		Default:         *f.ptr(&defaults),
+		RequiresRestart: f.restart,
+		ReadOnly:        f.readOnly,
+		tsType:          f.tsType,
+		get:             func(s *Settings) any { return *f.ptr(s) },
+		set: func(s *Settings, value any) error {
+			v, err := f.decode(value)
+			if err != nil {
+				return err
+			}
+			if f.validate != nil {
+				if err := f.validate(v); err != nil {
+					return err
+				}
+			}
+			if f.assign != nil {
+				f.assign(s, v)
+			} else {
+				*f.ptr(s) = v
+			}
+			return nil
+		},
+	}
+}
+
+// settingsFields - реестр настроек. Новое поле Settings добавляется сюда, а не в switch по ключам.
+var settingsFields = []Field{
+	fieldSpec[int]{
+		name: "version", typ: FieldNumber, tsType: "number",
+		ptr:    func(s *Settings) *int { return &s.Version },
+		decode: decodeInt, readOnly: true,
+	}.build(),
+	fieldSpec[uint32]{
+		name: "width", typ: FieldNumber, tsType: "number",
+		ptr:    func(s *Settings) *uint32 { return &s.Width },
+		decode: decodeUint32, restart: true,
+	}.build(),
+	fieldSpec[uint32]{
+		name: "height", typ: FieldNumber, tsType: "number",
+		ptr:    func(s *Settings) *uint32 { return &s.Height },
+		decode: decodeUint32, restart: true,
+	}.build(),
+	fieldSpec[string]{
+		name: "language", typ: FieldString, tsType: "string",
+		ptr:      func(s *Settings) *string { return &s.Language },
+		decode:   decodeString,
+		validate: notEmpty,
+	}.build(),
+	fieldSpec[bool]{
+		name: "first_run", typ: FieldBool, tsType: "boolean",
+		ptr:    func(s *Settings) *bool { return &s.FirstRun },
+		decode: decodeBool,
+	}.build(),
+	fieldSpec[[]string]{
+		name: "all_paths", typ: FieldStringList, tsType: "string[]",
+		ptr:    func(s *Settings) *[]string { return &s.AllPaths },
+		decode: decodeStringList,
+		assign: func(s *Settings, v []string) { s.setAllPaths(v) },
+	}.build(),
+	fieldSpec[string]{
+		name: "game_path", typ: FieldString, tsType: "string",
+		ptr:    func(s *Settings) *string { return &s.GamePath },
+		decode: decodeString,
+		assign: func(s *Settings, v string) { s.setGamePath(v) },
+	}.build(),
+	fieldSpec[string]{
+		name: "theme", typ: FieldString, tsType: "string",
+		ptr:      func(s *Settings) *string { return &s.Theme },
+		decode:   decodeString,
+		validate: notEmpty,
+	}.build(),
+	fieldSpec[paths_scanner.ScanRules]{
+		name: "scan_rules", typ: FieldObject, tsType: "ScanRules",
+		ptr:    func(s *Settings) *paths_scanner.ScanRules { return &s.ScanRules },
+		decode: decodeScanRules,
+	}.build(),
+	fieldSpec[[]Install]{
+		name: "installs", typ: FieldObject, tsType: "Install[]",
+		ptr:      func(s *Settings) *[]Install { return &s.Installs },
+		decode:   readOnlyDecode[[]Install],
+		readOnly: true,
+	}.build(),
+	fieldSpec[string]{
+		name: "active_install_id", typ: FieldString, tsType: "string",
+		ptr:      func(s *Settings) *string { return &s.ActiveInstallID },
+		decode:   decodeString,
+		readOnly: true,
+	}.build(),
+	fieldSpec[config_watcher.Mode]{
+		name: "watch_mode", typ: FieldString, tsType: `"auto" | "native" | "poll"`,
+		ptr: func(s *Settings) *config_watcher.Mode { return &s.WatchMode },
+		decode: func(value any) (config_watcher.Mode, error) {
+			v, err := decodeString(value)
+			return config_watcher.Mode(v), err
+		},
+		validate: config_watcher.Mode.Validate,
+	}.build(),
+	fieldSpec[int]{
+		name: "watch_poll_interval_ms", typ: FieldNumber, tsType: "number",
+		ptr:    func(s *Settings) *int { return &s.WatchPollIntervalMs },
+		decode: decodeInt,
+		validate: func(v int) error {
+			if v < 100 {
+				return fmt.Errorf("интервал опроса должен быть не меньше 100 мс")
+			}
+			return nil
+		},
+	}.build(),
+	fieldSpec[map[string]WindowState]{
+		name: "windows", typ: FieldObject, tsType: "Record<string, WindowState>",
+		ptr:      func(s *Settings) *map[string]WindowState { return &s.Windows },
+		decode:   readOnlyDecode[map[string]WindowState],
+		readOnly: true,
+	}.build(),
+}
+
+// fieldsByName - индекс реестра по имени поля.
+var fieldsByName = func() map[string]*Field {
+	m := make(map[string]*Field, len(settingsFields))
+	for i := range settingsFields {
+		m[settingsFields[i].Name] = &settingsFields[i]
+	}
+	return m
+}()
+
+// FieldError - ошибка в значении одной настройки.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError возвращается из UpdateSettings, если хотя бы одно значение некорректно.
+// Фронтенд получает её в cause ошибки вызова и может показать сообщение рядом с каждым полем.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "некорректные настройки: " + strings.Join(msgs, "; ")
+}
+
+// applyFields записывает значения из карты в s. Применяются либо все значения, либо ни одного:
+// при ошибках s не меняется и возвращается *ValidationError со всеми найденными ошибками.
+// Возвращает true, если хотя бы одно поле изменилось.
+func applyFields(s *Settings, values map[string]any) (bool, error) {
+	next := s.clone()
+	var errs []FieldError
+
+	// Порядок реестра, а не карты: поля, зависящие друг от друга (all_paths и game_path),
+	// применяются всегда одинаково
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return fieldOrder(names[i]) < fieldOrder(names[j]) })
+
+	for _, name := range names {
+		f, ok := fieldsByName[name]
+		switch {
+		case !ok:
+			errs = append(errs, FieldError{Field: name, Message: "неизвестная настройка"})
+		case f.ReadOnly:
+			errs = append(errs, FieldError{Field: name, Message: "настройка только для чтения"})
+		default:
+			if err := f.set(&next, values[name]); err != nil {
+				errs = append(errs, FieldError{Field: name, Message: err.Error()})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return false, &ValidationError{Errors: errs}
+	}
+
+	next.normalize()
+	if settingsEqual(*s, next) {
+		return false, nil
+	}
+	*s = next
+	return true, nil
+}
+
+// settingsEqual сравнивает настройки по значениям. Время последнего использования установки
+// сравнивается через time.Equal: показания монотонных часов и часовой пояс на равенство не влияют.
+// nil и пустой список считаются равными - в settings.json они отличаются только до normalize.
+func settingsEqual(a, b Settings) bool {
+	return a.Version == b.Version &&
+		a.Width == b.Width &&
+		a.Height == b.Height &&
+		a.Language == b.Language &&
+		a.GamePath == b.GamePath &&
+		a.FirstRun == b.FirstRun &&
+		slices.Equal(a.AllPaths, b.AllPaths) &&
+		a.Theme == b.Theme &&
+		scanRulesEqual(a.ScanRules, b.ScanRules) &&
+		slices.EqualFunc(a.Installs, b.Installs, installEqual) &&
+		a.ActiveInstallID == b.ActiveInstallID &&
+		a.WatchMode == b.WatchMode &&
+		a.WatchPollIntervalMs == b.WatchPollIntervalMs &&
+		maps.Equal(a.Windows, b.Windows)
+}
+
+func scanRulesEqual(a, b paths_scanner.ScanRules) bool {
+	return slices.Equal(a.Roots, b.Roots) &&
+		a.MaxDepth == b.MaxDepth &&
+		slices.Equal(a.ExcludedFolders, b.ExcludedFolders) &&
+		slices.Equal(a.TargetPatterns, b.TargetPatterns) &&
+		a.Workers == b.Workers &&
+		a.RootTimeoutSec == b.RootTimeoutSec
+}
+
+func installEqual(a, b Install) bool {
+	return a.ID == b.ID &&
+		a.Path == b.Path &&
+		a.Label == b.Label &&
+		a.GameVersion == b.GameVersion &&
+		a.LastUsed.Equal(b.LastUsed) &&
+		a.ActiveProfile == b.ActiveProfile &&
+		a.WatchEnabled == b.WatchEnabled
+}
+
+// fieldOrder возвращает позицию поля в реестре; неизвестные поля идут в конце.
+func fieldOrder(name string) int {
+	for i, f := range settingsFields {
+		if f.Name == name {
+			return i
+		}
+	}
+	return len(settingsFields)
+}
+
+// Fields возвращает описание всех настроек.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) Fields() []Field {
+	return append([]Field(nil), settingsFields...)
+}
+
+// ValidateSettings проверяет значения, не сохраняя их, и возвращает ошибки по полям.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) ValidateSettings(values map[string]any) []FieldError {
+	current := a.GetSettings()
+	if _, err := applyFields(&current, values); err != nil {
+		if verr, ok := err.(*ValidationError); ok {
+			return verr.Errors
+		}
+		return []FieldError{{Message: err.Error()}}
+	}
+	return []FieldError{}
+}
+
+// TypeScriptDefinitions возвращает объявления типов TypeScript для настроек,
+// построенные по реестру полей. Используется генератором frontend/src/types/settings.d.ts.
+func TypeScriptDefinitions() string {
+	var b strings.Builder
+	b.WriteString("// Этот файл создан автоматически из реестра настроек (backend/modules/app_settings/fields.go).\n")
+	b.WriteString("// Не редактируйте вручную: запустите task common:generate:settings-types.\n\n")
+	b.WriteString("import type { Install, WindowState } from \"../../bindings/lce/backend/modules/app_settings/models\";\n")
+	b.WriteString("import type { ScanRules } from \"../../bindings/lce/backend/modules/paths_scanner/models\";\n\n")
+
+	b.WriteString("export interface Settings {\n")
+	for _, f := range settingsFields {
+		var notes []string
+		if f.ReadOnly {
+			notes = append(notes, "только для чтения")
+		}
+		if f.RequiresRestart {
+			notes = append(notes, "применяется после перезапуска")
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, "  /** %s */\n", strings.Join(notes, ", "))
+		}
+		if f.ReadOnly {
+			b.WriteString("  readonly ")
+		} else {
+			b.WriteString("  ")
+		}
+		fmt.Fprintf(&b, "%s: %s;\n", f.Name, f.tsType)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("/** Поля, которые можно передать в UpdateSettings. */\n")
+	b.WriteString("export type SettingsUpdate = Partial<Pick<Settings,")
+	first := true
+	for _, f := range settingsFields {
+		if f.ReadOnly {
+			continue
+		}
+		if !first {
+			b.WriteString(" |")
+		}
+		fmt.Fprintf(&b, "\n  %q", f.Name)
+		first = false
+	}
+	b.WriteString("\n>>;\n\n")
+
+	b.WriteString("export type SettingsKey = keyof Settings;\n\n")
+	b.WriteString("/** Настройки, вступающие в силу после перезапуска. */\n")
+	b.WriteString("export type RestartRequiredSetting =")
+	var restart []string
+	for _, f := range settingsFields {
+		if f.RequiresRestart {
+			restart = append(restart, fmt.Sprintf("%q", f.Name))
+		}
+	}
+	if len(restart) == 0 {
+		restart = append(restart, "never")
+	}
+	b.WriteString(" " + strings.Join(restart, " | "))
+	b.WriteString(";\n\n")
+
+	b.WriteString("export interface FieldError {\n  field: string;\n  message: string;\n}\n")
+	return b.String()
+}
+
+func decodeString(value any) (string, error) {
+	v, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("ожидается строка, получено %s", jsonTypeName(value))
+	}
+	return v, nil
+}
+
+func decodeBool(value any) (bool, error) {
+	v, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("ожидается true или false, получено %s", jsonTypeName(value))
+	}
+	return v, nil
+}
+
+// decodeWhole принимает число из JSON (float64) и проверяет, что оно целое и лежит в [min, max].
+func decodeWhole(value any, min, max float64) (float64, error) {
+	v, ok := value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("ожидается число, получено %s", jsonTypeName(value))
+	}
+	if v != math.Trunc(v) || v < min || v > max {
+		return 0, fmt.Errorf("ожидается целое число от %.0f до %.0f", min, max)
+	}
+	return v, nil
+}
+
+func decodeUint32(value any) (uint32, error) {
+	v, err := decodeWhole(value, 0, math.MaxUint32)
+	return uint32(v), err
+}
+
+func decodeInt(value any) (int, error) {
+	v, err := decodeWhole(value, 0, math.MaxInt32)
+	return int(v), err
+}
+
+func decodeStringList(value any) ([]string, error) {
+	switch v := value.(type) {
+	case []string:
+		return append([]string{}, v...), nil
+	case []any:
+		list := make([]string, 0, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("элемент %d: ожидается строка, получено %s", i, jsonTypeName(item))
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("ожидается список строк, получено %s", jsonTypeName(value))
+	}
+}
+
+// readOnlyDecode используется полями только для чтения: applyFields до него не доходит.
+func readOnlyDecode[T any](any) (T, error) {
+	var zero T
+	return zero, fmt.Errorf("настройка только для чтения")
+}
+
+func notEmpty(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return fmt.Errorf("значение не может быть пустым")
+	}
+	return nil
+}
+
+// jsonTypeName возвращает название типа значения JSON для сообщений об ошибках.
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "строка"
+	case bool:
+		return "логическое значение"
+	case float64:
+		return "число"
+	case []any:
+		return "список"
+	case map[string]any:
+		return "объект"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,182 @@
+package app_settings
+
+import (
+	"errors"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"lce/backend/modules/config_watcher"
+	"lce/backend/modules/paths_scanner"
+)
+
+func TestApplyFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]any
+		check  func(s Settings) bool
+		errors []string // поля с ошибками; пусто - значения применяются
+	}{
+		{
+			name:   "строка",
+			values: map[string]any{"theme": "dark", "language": "ru"},
+			check:  func(s Settings) bool { return s.Theme == "dark" && s.Language == "ru" },
+		},
+		{
+			name:   "числа",
+			values: map[string]any{"width": float64(1280), "height": float64(720), "watch_poll_interval_ms": float64(100)},
+			check:  func(s Settings) bool { return s.Width == 1280 && s.Height == 720 && s.WatchPollIntervalMs == 100 },
+		},
+		{
+			name:   "логическое значение",
+			values: map[string]any{"first_run": false},
+			check:  func(s Settings) bool { return !s.FirstRun },
+		},
+		{
+			name:   "режим наблюдения",
+			values: map[string]any{"watch_mode": "poll"},
+			check:  func(s Settings) bool { return s.WatchMode == config_watcher.ModePoll },
+		},
+		{
+			name: "правила сканирования",
+			values: map[string]any{"scan_rules": map[string]any{
+				"roots": []any{`D:\`}, "max_depth": float64(3), "target_patterns": []any{"war3.exe"},
+			}},
+			check: func(s Settings) bool {
+				return reflect.DeepEqual(s.ScanRules.Roots, []string{`D:\`}) && s.ScanRules.MaxDepth == 3 &&
+					reflect.DeepEqual(s.ScanRules.TargetPatterns, []string{"war3.exe"})
+			},
+		},
+		{
+			name:   "правила сканирования без корней",
+			values: map[string]any{"scan_rules": map[string]any{"target_patterns": []any{"*.ini"}}},
+			check:  func(s Settings) bool { return s.ScanRules.Roots != nil && len(s.ScanRules.Roots) == 0 },
+		},
+
+		{name: "строка вместо числа", values: map[string]any{"width": "1280"}, errors: []string{"width"}},
+		{name: "число вместо строки", values: map[string]any{"theme": float64(1)}, errors: []string{"theme"}},
+		{name: "null вместо логического значения", values: map[string]any{"first_run": nil}, errors: []string{"first_run"}},
+		{name: "не список строк", values: map[string]any{"all_paths": []any{"C:\\W3", float64(1)}}, errors: []string{"all_paths"}},
+		{name: "объект неверного вида", values: map[string]any{"scan_rules": map[string]any{"max_depth": "5"}}, errors: []string{"scan_rules"}},
+		{name: "пустая строка", values: map[string]any{"language": "  "}, errors: []string{"language"}},
+		{name: "неизвестный режим", values: map[string]any{"watch_mode": "sometimes"}, errors: []string{"watch_mode"}},
+
+		{name: "дробное число", values: map[string]any{"width": 1280.5}, errors: []string{"width"}},
+		{name: "отрицательное число", values: map[string]any{"height": float64(-1)}, errors: []string{"height"}},
+		{name: "больше uint32", values: map[string]any{"width": float64(math.MaxUint32) + 1}, errors: []string{"width"}},
+		{name: "интервал меньше минимума", values: map[string]any{"watch_poll_interval_ms": float64(99)}, errors: []string{"watch_poll_interval_ms"}},
+		{name: "отрицательная глубина", values: map[string]any{"scan_rules": map[string]any{"max_depth": float64(-1), "target_patterns": []any{"war3.exe"}}}, errors: []string{"scan_rules"}},
+		{name: "без шаблонов", values: map[string]any{"scan_rules": map[string]any{"target_patterns": []any{}}}, errors: []string{"scan_rules"}},
+
+		{name: "неизвестная настройка", values: map[string]any{"colour": "red"}, errors: []string{"colour"}},
+		{name: "только для чтения", values: map[string]any{"version": float64(1), "installs": []any{}}, errors: []string{"version", "installs"}},
+		{
+			name:   "все ошибки сразу, без частичного применения",
+			values: map[string]any{"theme": "dark", "width": "wide", "watch_mode": "sometimes"},
+			errors: []string{"width", "watch_mode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := DefaultSettings()
+			before := s.clone()
+			changed, err := applyFields(&s, tt.values)
+
+			if len(tt.errors) == 0 {
+				if err != nil {
+					t.Fatalf("applyFields() = %v", err)
+				}
+				if !changed || !tt.check(s) {
+					t.Errorf("applyFields() = %v, настройки %+v", changed, s)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("applyFields() = %v, want *ValidationError", err)
+			}
+			var fields []string
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			if !sameElements(fields, tt.errors) {
+				t.Errorf("ошибки в полях %q, want %q", fields, tt.errors)
+			}
+			if changed || !settingsEqual(s, before) {
+				t.Errorf("при ошибке настройки изменены: %+v", s)
+			}
+		})
+	}
+}
+
+func TestApplyFieldsUnchanged(t *testing.T) {
+	s := DefaultSettings()
+	changed, err := applyFields(&s, map[string]any{"theme": s.Theme, "scan_rules": paths_scanner.DefaultScanRules()})
+	if err != nil || changed {
+		t.Errorf("applyFields() с текущими значениями = %v, %v; want false, nil", changed, err)
+	}
+}
+
+func TestSettingsEqual(t *testing.T) {
+	now := time.Now()
+	a := DefaultSettings()
+	a.Installs = []Install{{ID: "a", Path: "/games/A", LastUsed: now}}
+	a.Windows = map[string]WindowState{"MainWindow": {Width: 800}}
+
+	// Время без показаний монотонных часов и в другом часовом поясе - тот же момент
+	b := a.clone()
+	b.Installs[0].LastUsed = now.Round(0).In(time.FixedZone("MSK", 3*60*60))
+	if !settingsEqual(a, b) {
+		t.Error("settingsEqual() различает одинаковые моменты времени")
+	}
+
+	changes := map[string]func(s *Settings){
+		"all_paths":  func(s *Settings) { s.AllPaths = append(s.AllPaths, "/games/B") },
+		"scan_rules": func(s *Settings) { s.ScanRules.TargetPatterns = []string{"war3.exe"} },
+		"installs":   func(s *Settings) { s.Installs[0].WatchEnabled = !s.Installs[0].WatchEnabled },
+		"last_used":  func(s *Settings) { s.Installs[0].LastUsed = now.Add(time.Second) },
+		"windows":    func(s *Settings) { s.Windows["MainWindow"] = WindowState{Width: 801} },
+		"watch_mode": func(s *Settings) { s.WatchMode = config_watcher.ModePoll },
+	}
+	for name, change := range changes {
+		c := a.clone()
+		change(&c)
+		if settingsEqual(a, c) {
+			t.Errorf("settingsEqual() не заметил изменения %s", name)
+		}
+	}
+}
+
+// Файл объявлений TypeScript хранится в репозитории; после изменения реестра его нужно
+// перегенерировать командой task common:generate:settings-types.
+func TestTypeScriptDefinitionsUpToDate(t *testing.T) {
+	want, err := os.ReadFile("../../../frontend/src/types/settings.d.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := TypeScriptDefinitions()
+	if strings.ReplaceAll(string(want), "\r\n", "\n") != got {
+		t.Errorf("frontend/src/types/settings.d.ts устарел, запустите task common:generate:settings-types.\nwant:\n%s", got)
+	}
+}
+
+func sameElements(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, s := range a {
+		seen[s]++
+	}
+	for _, s := range b {
+		if seen[s]--; seen[s] < 0 {
+			return false
+		}
+	}
+	return true
+}
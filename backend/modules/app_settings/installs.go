@@ -0,0 +1,352 @@
+package app_settings
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"lce/backend/modules/paths_scanner"
+)
+
+// EventInstallSwitched отправляется при смене активной установки (данные: Install).
+// На него подписываются config_editor и config_watcher, чтобы перейти на новый config.lod.ini.
+const EventInstallSwitched = "install-switched"
+
+// Install - установка игры, известная приложению, со своим состоянием.
+type Install struct {
+	ID            string    `json:"id"`
+	Path          string    `json:"path"`
+	Label         string    `json:"label"`
+	GameVersion   string    `json:"game_version"`   // версия war3.exe на момент добавления или переключения
+	LastUsed      time.Time `json:"last_used"`      // когда установка последний раз становилась активной
+	ActiveProfile string    `json:"active_profile"` // выбранный профиль настроек для этой установки
+	WatchEnabled  bool      `json:"watch_enabled"`  // следить ли за внешними изменениями config.lod.ini
+}
+
+// ConfigPath возвращает путь к config.lod.ini этой установки.
+func (i Install) ConfigPath() string {
+	return filepath.Join(i.Path, "config.lod.ini")
+}
+
+// newInstallID генерирует случайный идентификатор установки.
+func newInstallID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// newInstall создаёт запись об установке для папки path.
+func newInstall(path, label string) Install {
+	path = filepath.Clean(path)
+	if label == "" {
+		label = filepath.Base(path)
+	}
+	return Install{
+		ID:           newInstallID(),
+		Path:         path,
+		Label:        label,
+		GameVersion:  paths_scanner.DetectGameVersion(path),
+		WatchEnabled: true,
+	}
+}
+
+// samePath сравнивает пути без учёта регистра и завершающих разделителей (как в Windows).
+func samePath(a, b string) bool {
+	return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+}
+
+// clone возвращает копию настроек, не разделяющую срезы с оригиналом.
+// Пустые срезы остаются пустыми, а не nil: в JSON это [] и null.
+func (s Settings) clone() Settings {
+	c := s
+	c.AllPaths = cloneSlice(s.AllPaths)
+	c.Installs = cloneSlice(s.Installs)
+	c.ScanRules.Roots = cloneSlice(s.ScanRules.Roots)
+	c.ScanRules.ExcludedFolders = cloneSlice(s.ScanRules.ExcludedFolders)
+	c.ScanRules.TargetPatterns = cloneSlice(s.ScanRules.TargetPatterns)
+	if s.Windows != nil {
+		c.Windows = make(map[string]WindowState, len(s.Windows))
+		for name, w := range s.Windows {
+			c.Windows[name] = w
+		}
+	}
+	return c
+}
+
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+// installIndex возвращает индекс установки с идентификатором id или -1.
+func (s *Settings) installIndex(id string) int {
+	for i, inst := range s.Installs {
+		if inst.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// installIndexByPath возвращает индекс установки с путём path или -1.
+func (s *Settings) installIndexByPath(path string) int {
+	for i, inst := range s.Installs {
+		if samePath(inst.Path, path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// activeInstall возвращает активную установку.
+func (s *Settings) activeInstall() (Install, bool) {
+	if i := s.installIndex(s.ActiveInstallID); i >= 0 {
+		return s.Installs[i], true
+	}
+	return Install{}, false
+}
+
+// ActiveInstall возвращает активную установку, если она выбрана.
+func (s Settings) ActiveInstall() (Install, bool) {
+	return s.activeInstall()
+}
+
+// syncLegacyPaths обновляет поля GamePath и AllPaths по списку установок,
+// чтобы фронтенд, работающий со старыми полями, видел актуальные данные.
+func (s *Settings) syncLegacyPaths() {
+	s.AllPaths = make([]string, 0, len(s.Installs))
+	for _, inst := range s.Installs {
+		s.AllPaths = append(s.AllPaths, inst.Path)
+	}
+	if inst, ok := s.activeInstall(); ok {
+		s.GamePath = inst.Path
+	} else {
+		s.ActiveInstallID = ""
+		s.GamePath = ""
+	}
+}
+
+// addInstallPath добавляет установку для path, если её ещё нет, и возвращает её индекс.
+func (s *Settings) addInstallPath(path, label string) int {
+	if i := s.installIndexByPath(path); i >= 0 {
+		return i
+	}
+	s.Installs = append(s.Installs, newInstall(path, label))
+	return len(s.Installs) - 1
+}
+
+// activate делает установку с индексом i активной.
+func (s *Settings) activate(i int) {
+	s.Installs[i].LastUsed = time.Now()
+	if version := paths_scanner.DetectGameVersion(s.Installs[i].Path); version != "" {
+		s.Installs[i].GameVersion = version
+	}
+	s.ActiveInstallID = s.Installs[i].ID
+}
+
+// setAllPaths добавляет установки для путей из поля all_paths, которых ещё нет в списке.
+// Установки отсюда не удаляются: список приходит, например, из прерванного или неполного
+// сканирования, и пропавший в нём путь не значит, что пользователь хочет забыть установку.
+// Удаляются установки явно, через RemoveInstall и RemoveInstallPath.
+func (s *Settings) setAllPaths(paths []string) {
+	for _, p := range paths {
+		if p != "" {
+			s.addInstallPath(p, "")
+		}
+	}
+	s.syncLegacyPaths()
+}
+
+// setGamePath делает активной установку с путём path (поле game_path), добавляя её при необходимости.
+func (s *Settings) setGamePath(path string) {
+	if path == "" {
+		s.ActiveInstallID = ""
+	} else if i := s.installIndexByPath(path); i < 0 || s.Installs[i].ID != s.ActiveInstallID {
+		s.activate(s.addInstallPath(path, ""))
+	}
+	s.syncLegacyPaths()
+}
+
+// migrateLegacyPaths создаёт записи установок из старых полей game_path и all_paths.
+// Возвращает true, если настройки изменились.
+func (s *Settings) migrateLegacyPaths() bool {
+	if len(s.Installs) > 0 || (len(s.AllPaths) == 0 && s.GamePath == "") {
+		return false
+	}
+	for _, p := range s.AllPaths {
+		if p != "" {
+			s.addInstallPath(p, "")
+		}
+	}
+	if s.GamePath != "" {
+		i := s.addInstallPath(s.GamePath, "")
+		s.ActiveInstallID = s.Installs[i].ID
+	}
+	s.syncLegacyPaths()
+	return true
+}
+
+// updateInstalls применяет изменение f к копии настроек и сохраняет результат.
+func (a *AppSettings) updateInstalls(f func(s *Settings) error) error {
+	_, err := a.store.Update(func(s *Settings) error {
+		if err := f(s); err != nil {
+			return err
+		}
+		s.syncLegacyPaths()
+		return nil
+	})
+	return err
+}
+
+// GetInstalls возвращает список известных установок.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) GetInstalls() []Install {
+	return a.store.Get().Installs
+}
+
+// GetActiveInstall возвращает активную установку или ошибку, если она не выбрана.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) GetActiveInstall() (Install, error) {
+	settings := a.store.Get()
+	inst, ok := settings.activeInstall()
+	if !ok {
+		return Install{}, fmt.Errorf("активная установка не выбрана")
+	}
+	return inst, nil
+}
+
+// AddInstall добавляет установку для папки path. Если такая уже есть, возвращается существующая.
+// Первая добавленная установка сразу становится активной.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) AddInstall(path, label string) (Install, error) {
+	if strings.TrimSpace(path) == "" {
+		return Install{}, fmt.Errorf("путь установки не указан")
+	}
+	var added Install
+	err := a.updateInstalls(func(s *Settings) error {
+		i := s.addInstallPath(path, strings.TrimSpace(label))
+		if s.ActiveInstallID == "" {
+			s.activate(i)
+		}
+		added = s.Installs[i]
+		return nil
+	})
+	return added, err
+}
+
+// RemoveInstall удаляет установку. Если она была активной, активной становится
+// последняя использованная из оставшихся.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) RemoveInstall(id string) error {
+	return a.updateInstalls(func(s *Settings) error {
+		i := s.installIndex(id)
+		if i < 0 {
+			return fmt.Errorf("установка '%s' не найдена", id)
+		}
+		s.removeInstall(i)
+		return nil
+	})
+}
+
+// RemoveInstallPath удаляет установку с папкой path, как RemoveInstall.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) RemoveInstallPath(path string) error {
+	return a.updateInstalls(func(s *Settings) error {
+		i := s.installIndexByPath(path)
+		if i < 0 {
+			return fmt.Errorf("установка в '%s' не найдена", path)
+		}
+		s.removeInstall(i)
+		return nil
+	})
+}
+
+// removeInstall удаляет установку с индексом i и при необходимости выбирает новую активную.
+func (s *Settings) removeInstall(i int) {
+	id := s.Installs[i].ID
+	s.Installs = append(s.Installs[:i], s.Installs[i+1:]...)
+	if s.ActiveInstallID != id {
+		return
+	}
+	s.ActiveInstallID = ""
+	next := -1
+	for j, inst := range s.Installs {
+		if next < 0 || inst.LastUsed.After(s.Installs[next].LastUsed) {
+			next = j
+		}
+	}
+	if next >= 0 {
+		s.activate(next)
+	}
+}
+
+// RelabelInstall меняет отображаемое имя установки.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) RelabelInstall(id, label string) (Install, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return Install{}, fmt.Errorf("имя установки не может быть пустым")
+	}
+	return a.modifyInstall(id, func(inst *Install) { inst.Label = label })
+}
+
+// SwitchInstall делает установку активной. config_editor и config_watcher
+// переключаются на неё по событию EventInstallSwitched.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) SwitchInstall(id string) (Install, error) {
+	var active Install
+	err := a.updateInstalls(func(s *Settings) error {
+		i := s.installIndex(id)
+		if i < 0 {
+			return fmt.Errorf("установка '%s' не найдена", id)
+		}
+		s.activate(i)
+		active = s.Installs[i]
+		return nil
+	})
+	return active, err
+}
+
+// SetInstallProfile запоминает активный профиль настроек установки.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) SetInstallProfile(id, profile string) (Install, error) {
+	return a.modifyInstall(id, func(inst *Install) { inst.ActiveProfile = profile })
+}
+
+// SetInstallWatching включает или выключает наблюдение за config.lod.ini установки.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) SetInstallWatching(id string, enabled bool) (Install, error) {
+	return a.modifyInstall(id, func(inst *Install) { inst.WatchEnabled = enabled })
+}
+
+// modifyInstall применяет f к установке с идентификатором id.
+func (a *AppSettings) modifyInstall(id string, f func(inst *Install)) (Install, error) {
+	var modified Install
+	err := a.updateInstalls(func(s *Settings) error {
+		i := s.installIndex(id)
+		if i < 0 {
+			return fmt.Errorf("установка '%s' не найдена", id)
+		}
+		f(&s.Installs[i])
+		modified = s.Installs[i]
+		return nil
+	})
+	return modified, err
+}
+
+// KnownInstallPaths возвращает пути всех известных установок (для фоновой проверки в paths_scanner).
+func (a *AppSettings) KnownInstallPaths() []string {
+	installs := a.store.Get().Installs
+	paths := make([]string, 0, len(installs))
+	for _, inst := range installs {
+		paths = append(paths, inst.Path)
+	}
+	return paths
+}
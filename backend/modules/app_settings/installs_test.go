@@ -0,0 +1,158 @@
+package app_settings
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// installDirs возвращает пути к папкам установок во временной папке.
+func installDirs(t *testing.T, names ...string) []string {
+	t.Helper()
+	root := t.TempDir()
+	dirs := make([]string, len(names))
+	for i, name := range names {
+		dirs[i] = filepath.Join(root, name)
+	}
+	return dirs
+}
+
+func TestInstallsAddSwitchRemove(t *testing.T) {
+	a := NewAppSettings(newTestStore(t))
+	dirs := installDirs(t, "W3", "W3 Reforged")
+
+	first, err := a.AddInstall(dirs[0], "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Label != "W3" || !first.WatchEnabled {
+		t.Errorf("AddInstall() = %+v", first)
+	}
+	second, err := a.AddInstall(dirs[1], " Reforged ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := a.AddInstall(dirs[0]+string(filepath.Separator), ""); again.ID != first.ID {
+		t.Errorf("повторное добавление создало новую установку %+v", again)
+	}
+	if _, err := a.AddInstall("  ", ""); err == nil {
+		t.Error("AddInstall без пути не вернул ошибку")
+	}
+
+	// Первая добавленная установка сразу активна
+	s := a.GetSettings()
+	if s.ActiveInstallID != first.ID || s.GamePath != dirs[0] || second.Label != "Reforged" {
+		t.Fatalf("после добавления: active %q, game_path %q, label %q", s.ActiveInstallID, s.GamePath, second.Label)
+	}
+	if !reflect.DeepEqual(s.AllPaths, dirs) {
+		t.Errorf("all_paths = %q, want %q", s.AllPaths, dirs)
+	}
+
+	if _, err := a.SwitchInstall(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if s := a.GetSettings(); s.GamePath != dirs[1] {
+		t.Errorf("game_path после SwitchInstall = %q, want %q", s.GamePath, dirs[1])
+	}
+	if _, err := a.SwitchInstall("missing"); err == nil {
+		t.Error("SwitchInstall неизвестной установки не вернул ошибку")
+	}
+
+	// Удаление активной установки делает активной последнюю использованную из оставшихся
+	if err := a.RemoveInstall(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if s := a.GetSettings(); s.ActiveInstallID != first.ID || s.GamePath != dirs[0] || len(s.Installs) != 1 {
+		t.Errorf("после RemoveInstall: %+v", s.Installs)
+	}
+	if err := a.RemoveInstallPath(dirs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if s := a.GetSettings(); s.ActiveInstallID != "" || s.GamePath != "" || len(s.AllPaths) != 0 {
+		t.Errorf("после удаления всех установок: active %q, game_path %q, all_paths %q", s.ActiveInstallID, s.GamePath, s.AllPaths)
+	}
+	if err := a.RemoveInstallPath(dirs[0]); err == nil {
+		t.Error("RemoveInstallPath удалённой установки не вернул ошибку")
+	}
+}
+
+func TestInstallsLegacyFields(t *testing.T) {
+	st := newTestStore(t)
+	dirs := installDirs(t, "A", "B", "C")
+
+	// all_paths добавляет установки, не выбирая активную
+	s, err := st.UpdateFields(map[string]any{"all_paths": []any{dirs[0], dirs[1], ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Installs) != 2 || s.ActiveInstallID != "" || s.GamePath != "" {
+		t.Fatalf("после all_paths: %+v", s)
+	}
+
+	// game_path выбирает установку и добавляет её, если её не было
+	s, err = st.UpdateFields(map[string]any{"game_path": dirs[2]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, ok := s.ActiveInstall()
+	if !ok || active.Path != dirs[2] || s.GamePath != dirs[2] || !reflect.DeepEqual(s.AllPaths, dirs) {
+		t.Errorf("после game_path: active %+v, all_paths %q", active, s.AllPaths)
+	}
+
+	// Пустой game_path снимает выбор, но установку не удаляет
+	s, err = st.UpdateFields(map[string]any{"game_path": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ActiveInstallID != "" || s.GamePath != "" || len(s.Installs) != 3 {
+		t.Errorf("после пустого game_path: %+v", s)
+	}
+
+	// Старый settings.json без installs
+	legacy := Settings{AllPaths: []string{dirs[0], dirs[1]}, GamePath: dirs[1]}
+	if !legacy.migrateLegacyPaths() {
+		t.Fatal("migrateLegacyPaths() = false")
+	}
+	active, ok = legacy.ActiveInstall()
+	if len(legacy.Installs) != 2 || !ok || active.Path != dirs[1] || legacy.GamePath != dirs[1] {
+		t.Errorf("migrateLegacyPaths(): %+v", legacy)
+	}
+	if legacy.migrateLegacyPaths() {
+		t.Error("повторная миграция изменила настройки")
+	}
+}
+
+// Сканирование записывает найденные пути в all_paths. Прерванное или неполное сканирование
+// не должно удалять установки, которых оно не нашло, вместе с их состоянием.
+func TestInstallsRescanKeepsUnfound(t *testing.T) {
+	a := NewAppSettings(newTestStore(t))
+	dirs := installDirs(t, "A", "B", "C")
+	for _, dir := range dirs[:2] {
+		if _, err := a.AddInstall(dir, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := a.GetInstalls()[1]
+	if _, err := a.SwitchInstall(b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.RelabelInstall(b.ID, "Турнирная"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.SetInstallWatching(b.ID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Сканирование нашло A и новую C, но не B
+	if _, err := a.UpdateSettings(map[string]any{"all_paths": []any{dirs[0], dirs[2]}}); err != nil {
+		t.Fatal(err)
+	}
+	s := a.GetSettings()
+	if !reflect.DeepEqual(s.AllPaths, []string{dirs[0], dirs[1], dirs[2]}) {
+		t.Errorf("all_paths после сканирования = %q", s.AllPaths)
+	}
+	active, ok := s.ActiveInstall()
+	if !ok || active.ID != b.ID || active.Label != "Турнирная" || active.WatchEnabled || s.GamePath != dirs[1] {
+		t.Errorf("активная установка после сканирования = %+v, game_path %q", active, s.GamePath)
+	}
+}
@@ -0,0 +1,161 @@
+package app_settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"lce/backend/modules/data_dir"
+	"lce/backend/modules/paths_scanner"
+)
+
+// CurrentSettingsVersion - версия формата settings.json, которую записывает приложение.
+// При добавлении поля, которому нужна подготовка старых файлов, добавьте миграцию
+// в конец settingsMigrations и увеличьте версию.
+const CurrentSettingsVersion = 4
+
+// settingsMigration переводит настройки с версии to-1 на версию to.
+// s уже заполнены из файла поверх значений по умолчанию, raw - поля файла как есть,
+// чтобы отличить отсутствующее поле от явно заданного.
+type settingsMigration struct {
+	to          int
+	description string
+	apply       func(s *Settings, raw map[string]json.RawMessage)
+}
+
+// settingsMigrations - миграции в порядке возрастания версий.
+var settingsMigrations = []settingsMigration{
+	{
+		to:          1,
+		description: "заполнение языка, темы и списка путей",
+		apply: func(s *Settings, raw map[string]json.RawMessage) {
+			defaults := DefaultSettings()
+			if s.Language == "" {
+				s.Language = defaults.Language
+			}
+			if s.Theme == "" {
+				s.Theme = defaults.Theme
+			}
+			if s.AllPaths == nil {
+				s.AllPaths = []string{}
+			}
+		},
+	},
+	{
+		to:          2,
+		description: "правила сканирования",
+		apply: func(s *Settings, raw map[string]json.RawMessage) {
+			if _, ok := raw["scan_rules"]; !ok || len(s.ScanRules.TargetPatterns) == 0 {
+				s.ScanRules = paths_scanner.DefaultScanRules()
+			}
+		},
+	},
+	{
+		to:          3,
+		description: "установки из game_path и all_paths",
+		apply: func(s *Settings, raw map[string]json.RawMessage) {
+			if s.Installs == nil {
+				s.Installs = []Install{}
+			}
+			s.migrateLegacyPaths()
+		},
+	},
+	{
+		to:          4,
+		description: "режим наблюдения за файлами",
+		apply: func(s *Settings, raw map[string]json.RawMessage) {
+			defaults := DefaultSettings()
+			if s.WatchMode.Validate() != nil {
+				s.WatchMode = defaults.WatchMode
+			}
+			if s.WatchPollIntervalMs <= 0 {
+				s.WatchPollIntervalMs = defaults.WatchPollIntervalMs
+			}
+		},
+	},
+}
+
+func init() {
+	// Ошибка в порядке миграций - ошибка программиста, её нужно заметить сразу
+	for i, m := range settingsMigrations {
+		if m.to != i+1 {
+			panic(fmt.Sprintf("миграция настроек '%s' имеет версию %d, ожидалась %d", m.description, m.to, i+1))
+		}
+	}
+	if len(settingsMigrations) != CurrentSettingsVersion {
+		panic("CurrentSettingsVersion не совпадает с последней миграцией настроек")
+	}
+}
+
+// decodeSettings разбирает settings.json. Поля, отсутствующие в файле, получают значения
+// по умолчанию, а версия - 0, если файл записан до появления поля version.
+func decodeSettings(data []byte) (Settings, map[string]json.RawMessage, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Settings{}, nil, err
+	}
+	s := DefaultSettings()
+	s.Version = 0
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{}, nil, err
+	}
+	return s, raw, nil
+}
+
+// migrateSettings последовательно применяет миграции, начиная с версии s.Version.
+// Возвращает описания применённых миграций.
+func migrateSettings(s *Settings, raw map[string]json.RawMessage) []string {
+	var applied []string
+	for _, m := range settingsMigrations {
+		if m.to <= s.Version {
+			continue
+		}
+		m.apply(s, raw)
+		s.Version = m.to
+		applied = append(applied, fmt.Sprintf("v%d: %s", m.to, m.description))
+	}
+	return applied
+}
+
+// normalize исправляет значения, недопустимые в любой версии. Возвращает true, если что-то изменилось.
+func (s *Settings) normalize() bool {
+	changed := false
+	// Минимальные размеры
+	if s.Width < 650 {
+		s.Width = 650
+		changed = true
+	}
+	if s.Height < 350 {
+		s.Height = 350
+		changed = true
+	}
+	return changed
+}
+
+// backupSettingsFile сохраняет копию settings.json в папку резервных копий перед миграцией.
+func backupSettingsFile(settingsPath string, data []byte) (string, error) {
+	backupPath, err := data_dir.File(data_dir.Backups, filepath.Base(settingsPath)+".bak")
+	if err != nil {
+		return "", fmt.Errorf("не удалось сохранить резервную копию настроек: %w", err)
+	}
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return "", fmt.Errorf("не удалось сохранить резервную копию настроек: %w", err)
+	}
+	return backupPath, nil
+}
+
+// preserveBrokenSettings сохраняет нечитаемый settings.json в папку резервных копий
+// под отдельным именем, чтобы пользователь мог восстановить настройки вручную.
+func preserveBrokenSettings(settingsPath string, data []byte) (string, error) {
+	name := fmt.Sprintf("%s.broken-%s", filepath.Base(settingsPath), time.Now().Format("20060102-150405"))
+	brokenPath, err := data_dir.File(data_dir.Backups, name)
+	if err != nil {
+		return "", fmt.Errorf("не удалось сохранить копию повреждённых настроек: %w", err)
+	}
+	if err := os.WriteFile(brokenPath, data, 0644); err != nil {
+		return "", fmt.Errorf("не удалось сохранить копию повреждённых настроек: %w", err)
+	}
+	return brokenPath, nil
+}
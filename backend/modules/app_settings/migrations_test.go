@@ -0,0 +1,199 @@
+package app_settings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lce/backend/modules/config_watcher"
+	"lce/backend/modules/data_dir"
+	"lce/backend/modules/paths_scanner"
+)
+
+// useTestDataDir направляет data_dir во временную папку и возвращает путь к settings.json.
+func useTestDataDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	data_dir.Use(data_dir.Layout{Data: dir, Assets: dir})
+	path, err := SettingsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// migrateStep разбирает файл версии to-1 и применяет к нему только миграцию на версию to.
+func migrateStep(t *testing.T, to int, data string) Settings {
+	t.Helper()
+	s, raw, err := decodeSettings([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != to-1 {
+		t.Fatalf("версия файла %d, want %d", s.Version, to-1)
+	}
+	settingsMigrations[to-1].apply(&s, raw)
+	return s
+}
+
+func TestSettingsMigrationSteps(t *testing.T) {
+	defaults := DefaultSettings()
+
+	t.Run("v1", func(t *testing.T) {
+		s := migrateStep(t, 1, `{"language": "", "theme": "", "all_paths": null}`)
+		if s.Language != defaults.Language || s.Theme != defaults.Theme || s.AllPaths == nil {
+			t.Errorf("language %q, theme %q, all_paths %v", s.Language, s.Theme, s.AllPaths)
+		}
+		if s := migrateStep(t, 1, `{"language": "ru", "theme": "dark"}`); s.Language != "ru" || s.Theme != "dark" {
+			t.Errorf("заданные язык и тема заменены: %q, %q", s.Language, s.Theme)
+		}
+	})
+
+	t.Run("v2", func(t *testing.T) {
+		rules := paths_scanner.DefaultScanRules()
+		if s := migrateStep(t, 2, `{"version": 1}`); !reflect.DeepEqual(s.ScanRules, rules) {
+			t.Errorf("без scan_rules: %+v", s.ScanRules)
+		}
+		if s := migrateStep(t, 2, `{"version": 1, "scan_rules": {"roots": ["D:\\"], "target_patterns": []}}`); !reflect.DeepEqual(s.ScanRules, rules) {
+			t.Errorf("scan_rules без шаблонов: %+v", s.ScanRules)
+		}
+		// Недостающие поля правил берутся по умолчанию, заданные сохраняются
+		if s := migrateStep(t, 2, `{"version": 1, "scan_rules": {"roots": ["D:\\"]}}`); !reflect.DeepEqual(s.ScanRules.Roots, []string{`D:\`}) || !reflect.DeepEqual(s.ScanRules.TargetPatterns, rules.TargetPatterns) {
+			t.Errorf("scan_rules с корнями: %+v", s.ScanRules)
+		}
+		s := migrateStep(t, 2, `{"version": 1, "scan_rules": {"target_patterns": ["war3.exe"], "max_depth": 3}}`)
+		if !reflect.DeepEqual(s.ScanRules.TargetPatterns, []string{"war3.exe"}) || s.ScanRules.MaxDepth != 3 {
+			t.Errorf("заданные правила заменены: %+v", s.ScanRules)
+		}
+	})
+
+	t.Run("v3", func(t *testing.T) {
+		a, b := filepath.FromSlash("/games/A"), filepath.FromSlash("/games/B")
+		s := migrateStep(t, 3, `{"version": 2, "game_path": "`+filepath.ToSlash(b)+`", "all_paths": ["`+filepath.ToSlash(a)+`", "`+filepath.ToSlash(b)+`"]}`)
+		active, ok := s.ActiveInstall()
+		if len(s.Installs) != 2 || !ok || active.Path != b {
+			t.Errorf("installs %+v, active %+v", s.Installs, active)
+		}
+		if s := migrateStep(t, 3, `{"version": 2}`); s.Installs == nil || len(s.Installs) != 0 {
+			t.Errorf("без путей: installs %v", s.Installs)
+		}
+	})
+
+	t.Run("v4", func(t *testing.T) {
+		s := migrateStep(t, 4, `{"version": 3, "watch_mode": "sometimes", "watch_poll_interval_ms": 0}`)
+		if s.WatchMode != defaults.WatchMode || s.WatchPollIntervalMs != defaults.WatchPollIntervalMs {
+			t.Errorf("watch_mode %q, interval %d", s.WatchMode, s.WatchPollIntervalMs)
+		}
+		s = migrateStep(t, 4, `{"version": 3, "watch_mode": "poll", "watch_poll_interval_ms": 500}`)
+		if s.WatchMode != config_watcher.ModePoll || s.WatchPollIntervalMs != 500 {
+			t.Errorf("заданный режим заменён: %q, %d", s.WatchMode, s.WatchPollIntervalMs)
+		}
+	})
+}
+
+func TestLoadSettingsMigratesWithBackup(t *testing.T) {
+	path := useTestDataDir(t)
+	original := []byte(`{"language": "ru", "theme": "", "game_path": "/games/W3"}`)
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != CurrentSettingsVersion || s.Language != "ru" || s.Theme != DefaultSettings().Theme || len(s.Installs) != 1 {
+		t.Errorf("LoadSettings() = %+v", s)
+	}
+
+	backup, err := os.ReadFile(filepath.Join(data_dir.Path(data_dir.Backups), "settings.json.bak"))
+	if err != nil || string(backup) != string(original) {
+		t.Errorf("резервная копия = %q, %v; want исходный файл", backup, err)
+	}
+	saved, _, err := decodeSettings(mustRead(t, path))
+	if err != nil || saved.Version != CurrentSettingsVersion {
+		t.Errorf("settings.json после миграции: версия %d, %v", saved.Version, err)
+	}
+}
+
+func TestLoadSettingsCurrentVersionUnchanged(t *testing.T) {
+	path := useTestDataDir(t)
+	first, err := LoadSettings() // создаёт файл с настройками по умолчанию
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := mustRead(t, path)
+
+	second, err := LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustRead(t, path); string(got) != string(data) {
+		t.Errorf("файл текущей версии перезаписан:\n%s\nwant\n%s", got, data)
+	}
+	if !settingsEqual(first, second) {
+		t.Errorf("повторная загрузка = %+v, want %+v", second, first)
+	}
+	if _, err := os.Stat(data_dir.Path(data_dir.Backups)); !os.IsNotExist(err) {
+		t.Errorf("для файла текущей версии создана резервная копия: %v", err)
+	}
+	if applied := migrateSettings(&second, nil); len(applied) != 0 {
+		t.Errorf("migrateSettings() на текущей версии применил %v", applied)
+	}
+}
+
+func TestLoadSettingsKeepsBrokenFile(t *testing.T) {
+	path := useTestDataDir(t)
+	broken := []byte(`{"language": "ru",`)
+	if err := os.WriteFile(path, broken, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadSettings()
+	if err == nil {
+		t.Error("LoadSettings() с повреждённым файлом не вернул ошибку")
+	}
+	if !settingsEqual(s, DefaultSettings()) {
+		t.Errorf("LoadSettings() = %+v, want настройки по умолчанию", s)
+	}
+	entries, _ := os.ReadDir(data_dir.Path(data_dir.Backups))
+	var kept []byte
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "settings.json.broken-") {
+			kept = mustRead(t, filepath.Join(data_dir.Path(data_dir.Backups), e.Name()))
+		}
+	}
+	if string(kept) != string(broken) {
+		t.Errorf("копия повреждённого файла = %q, want %q", kept, broken)
+	}
+}
+
+func TestLoadSettingsBrokenFileWithoutBackup(t *testing.T) {
+	path := useTestDataDir(t)
+	broken := []byte(`{"language": "ru",`)
+	if err := os.WriteFile(path, broken, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Копию сохранить некуда: на месте папки резервных копий лежит файл
+	if err := os.WriteFile(data_dir.Path(data_dir.Backups), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadSettings(); err == nil {
+		t.Error("LoadSettings() с повреждённым файлом не вернул ошибку")
+	}
+	if got := mustRead(t, path); string(got) != string(broken) {
+		t.Errorf("повреждённый файл без копии перезаписан: %q", got)
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
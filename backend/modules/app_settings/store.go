@@ -0,0 +1,187 @@
+package app_settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// EventSettingsUpdated отправляется фронтенду после каждого изменения настроек (данные: Settings).
+const EventSettingsUpdated = "app-settings-updated"
+
+// EventEmitter отправляет события фронтенду (реализуется app.Event в Wails).
+type EventEmitter interface {
+	Emit(name string, data ...any)
+}
+
+// Observer получает настройки до и после изменения.
+// Вызывается синхронно в горутине, изменившей настройки; вызывать из него Update нельзя.
+type Observer func(previous, current Settings)
+
+// Store - единственный источник настроек в памяти. Все изменения проходят через Update,
+// сохраняются на диск и рассылаются подписчикам в порядке применения.
+type Store struct {
+	mu       sync.RWMutex
+	settings Settings
+
+	// notifyMu удерживается на время изменения и рассылки, чтобы подписчики видели изменения
+	// по порядку. Берётся до mu: подписчики читают настройки через Get, пока он захвачен
+	notifyMu  sync.Mutex
+	observers []subscription
+	nextID    uint64
+
+	firstLaunch bool // settings.json не было до запуска
+}
+
+type subscription struct {
+	id       uint64
+	observer Observer
+}
+
+// NewStore загружает настройки из settings.json. Если файл не читается, используются
+// настройки по умолчанию (подробности см. в LoadSettings).
+func NewStore() *Store {
+	firstLaunch := false
+	if path, err := SettingsPath(); err == nil {
+		_, statErr := os.Stat(path)
+		firstLaunch = os.IsNotExist(statErr)
+	}
+	s, err := LoadSettings()
+	if err != nil {
+		fmt.Printf("Ошибка при загрузке настроек: %v. Используются настройки по умолчанию.\n", err)
+	}
+	return &Store{settings: s, firstLaunch: firstLaunch}
+}
+
+// FirstLaunch сообщает, что settings.json создан при этом запуске: приложение запущено впервые
+// и настройки, которые выбираются по системе (например, язык), ещё не заданы пользователем.
+func (st *Store) FirstLaunch() bool {
+	return st.firstLaunch
+}
+
+// Get возвращает копию текущих настроек.
+func (st *Store) Get() Settings {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.settings.clone()
+}
+
+// Update применяет f к копии настроек, сохраняет результат и уведомляет подписчиков.
+// Если f вернула ошибку или сохранение не удалось, настройки не меняются.
+// Если настройки не изменились, файл не перезаписывается и уведомлений нет.
+func (st *Store) Update(f func(s *Settings) error) (Settings, error) {
+	st.notifyMu.Lock()
+	defer st.notifyMu.Unlock()
+	st.mu.Lock()
+	next := st.settings.clone()
+	if err := f(&next); err != nil {
+		defer st.mu.Unlock()
+		return st.settings.clone(), err
+	}
+	next.normalize()
+	if settingsEqual(st.settings, next) {
+		defer st.mu.Unlock()
+		return st.settings.clone(), nil
+	}
+	if err := SaveSettings(&next); err != nil {
+		defer st.mu.Unlock()
+		return st.settings.clone(), fmt.Errorf("не удалось сохранить настройки: %w", err)
+	}
+	return st.commit(next), nil
+}
+
+// UpdateFields изменяет настройки по именам полей из реестра settingsFields.
+// При некорректных значениях возвращается *ValidationError и ничего не меняется.
+func (st *Store) UpdateFields(values map[string]any) (Settings, error) {
+	return st.Update(func(s *Settings) error {
+		_, err := applyFields(s, values)
+		return err
+	})
+}
+
+// Reload перечитывает settings.json (например, после правки вручную) и, если настройки
+// на диске отличаются от текущих, применяет их и уведомляет подписчиков.
+// Файл читается под mu, как и пишется в Update: иначе сохранение, случившееся между чтением
+// и сравнением, было бы затёрто устаревшим содержимым файла.
+func (st *Store) Reload() (Settings, error) {
+	st.notifyMu.Lock()
+	defer st.notifyMu.Unlock()
+	st.mu.Lock()
+	// Файл с ошибкой (например, недописанный при ручной правке) не трогаем:
+	// LoadSettings заменил бы его настройками по умолчанию
+	if path, err := SettingsPath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil && !json.Valid(data) {
+			defer st.mu.Unlock()
+			return st.settings.clone(), fmt.Errorf("settings.json содержит некорректный JSON, изменения не применены")
+		}
+	}
+
+	loaded, err := LoadSettings()
+	if err != nil {
+		defer st.mu.Unlock()
+		return st.settings.clone(), fmt.Errorf("не удалось перечитать настройки: %w", err)
+	}
+	// Настройки, только что сохранённые самим приложением, совпадут с текущими
+	if settingsEqual(st.settings, loaded) {
+		defer st.mu.Unlock()
+		return st.settings.clone(), nil
+	}
+	fmt.Println("Настройки изменены вне приложения, применяем settings.json")
+	return st.commit(loaded), nil
+}
+
+// commit делает next текущими настройками и рассылает уведомления.
+// Вызывается с захваченными notifyMu и mu и освобождает mu: подписчики могут читать настройки через Get.
+func (st *Store) commit(next Settings) Settings {
+	previous := st.settings
+	st.settings = next
+	st.mu.Unlock()
+
+	for _, observer := range st.snapshotObservers() {
+		observer(previous.clone(), next.clone())
+	}
+	return next.clone()
+}
+
+// Subscribe регистрирует подписчика и возвращает функцию для отписки.
+func (st *Store) Subscribe(observer Observer) (unsubscribe func()) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.nextID++
+	id := st.nextID
+	st.observers = append(st.observers, subscription{id: id, observer: observer})
+	return func() {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		for i, sub := range st.observers {
+			if sub.id == id {
+				st.observers = append(st.observers[:i:i], st.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// snapshotObservers возвращает подписчиков в порядке подписки.
+func (st *Store) snapshotObservers() []Observer {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	list := make([]Observer, len(st.observers))
+	for i, sub := range st.observers {
+		list[i] = sub.observer
+	}
+	return list
+}
+
+// Publish пересылает изменения настроек фронтенду: EventSettingsUpdated после каждого изменения
+// и EventInstallSwitched при смене активной установки.
+func (st *Store) Publish(events EventEmitter) (unsubscribe func()) {
+	return st.Subscribe(func(previous, current Settings) {
+		events.Emit(EventSettingsUpdated, current)
+		if current.ActiveInstallID != previous.ActiveInstallID {
+			active, _ := current.activeInstall()
+			events.Emit(EventInstallSwitched, active)
+		}
+	})
+}
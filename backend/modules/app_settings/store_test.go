@@ -0,0 +1,190 @@
+package app_settings
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"lce/backend/modules/data_dir"
+)
+
+// newTestStore создаёт хранилище с settings.json во временной папке.
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	dir := t.TempDir()
+	data_dir.Use(data_dir.Layout{Data: dir, Assets: dir})
+	return NewStore()
+}
+
+type recordedEvent struct {
+	name string
+	data []any
+}
+
+type recordingEmitter struct{ events []recordedEvent }
+
+func (r *recordingEmitter) Emit(name string, data ...any) {
+	r.events = append(r.events, recordedEvent{name, data})
+}
+
+func TestStoreUpdateNotifiesAndPersists(t *testing.T) {
+	st := newTestStore(t)
+
+	var calls []string
+	st.Subscribe(func(previous, current Settings) {
+		// Подписчик может читать настройки: хранилище не держит блокировку во время рассылки
+		if got := st.Get().Theme; got != current.Theme {
+			t.Errorf("Get() в подписчике = %q, want %q", got, current.Theme)
+		}
+		calls = append(calls, previous.Theme+"->"+current.Theme)
+	})
+
+	if _, err := st.UpdateFields(map[string]any{"theme": "dark"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Update(func(s *Settings) error { s.Theme = "light"; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	// Без изменений уведомления нет
+	if _, err := st.UpdateFields(map[string]any{"theme": "light"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 2 || calls[0] != "default->dark" || calls[1] != "dark->light" {
+		t.Errorf("уведомления %v", calls)
+	}
+	loaded, err := LoadSettings()
+	if err != nil || loaded.Theme != "light" {
+		t.Errorf("LoadSettings() = %q, %v; want light", loaded.Theme, err)
+	}
+}
+
+func TestStoreRejectsInvalidUpdate(t *testing.T) {
+	st := newTestStore(t)
+	notified := false
+	st.Subscribe(func(Settings, Settings) { notified = true })
+
+	_, err := st.UpdateFields(map[string]any{"theme": "dark", "width": "wide", "installs": nil})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Errors) != 2 {
+		t.Fatalf("UpdateFields() error = %v, want ValidationError с двумя полями", err)
+	}
+	if st.Get().Theme != "default" || notified {
+		t.Errorf("некорректное обновление применилось частично: theme=%q, notified=%v", st.Get().Theme, notified)
+	}
+
+	if _, err := st.Update(func(*Settings) error { return errors.New("отказ") }); err == nil {
+		t.Error("ошибка из функции изменения не вернулась")
+	}
+}
+
+func TestStoreUnsubscribe(t *testing.T) {
+	st := newTestStore(t)
+	count := 0
+	unsubscribe := st.Subscribe(func(Settings, Settings) { count++ })
+
+	st.UpdateFields(map[string]any{"language": "ru"})
+	unsubscribe()
+	st.UpdateFields(map[string]any{"language": "en"})
+
+	if count != 1 {
+		t.Errorf("подписчик вызван %d раз, want 1", count)
+	}
+}
+
+func TestStorePublish(t *testing.T) {
+	st := newTestStore(t)
+	events := &recordingEmitter{}
+	st.Publish(events)
+
+	st.UpdateFields(map[string]any{"language": "ru"})
+	st.UpdateFields(map[string]any{"all_paths": []any{"C:/Games/War3"}, "game_path": "C:/Games/War3"})
+
+	var names []string
+	for _, e := range events.events {
+		names = append(names, e.name)
+	}
+	want := []string{EventSettingsUpdated, EventSettingsUpdated, EventInstallSwitched}
+	if len(names) != len(want) {
+		t.Fatalf("события %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("события %v, want %v", names, want)
+		}
+	}
+	if inst, ok := events.events[2].data[0].(Install); !ok || inst.Path == "" {
+		t.Errorf("данные %s = %+v", EventInstallSwitched, events.events[2].data)
+	}
+}
+
+func TestStoreReloadKeepsInvalidFile(t *testing.T) {
+	st := newTestStore(t)
+	path, err := SettingsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"theme": "dark",`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.Reload(); err == nil {
+		t.Error("Reload() с некорректным JSON не вернул ошибку")
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"theme": "dark",` {
+		t.Errorf("файл перезаписан: %s", data)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 4, "theme": "dark"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := st.Reload(); err != nil || s.Theme != "dark" || st.Get().Theme != "dark" {
+		t.Errorf("Reload() = %q, %v; want dark", s.Theme, err)
+	}
+}
+
+// Вотчер вызывает Reload после каждого сохранения settings.json, в том числе своего.
+// Reload, начавшийся до очередного Update, не должен вернуть прежние настройки.
+func TestStoreReloadDuringUpdates(t *testing.T) {
+	st := newTestStore(t)
+	const updates = 200
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= updates; i++ {
+			theme := fmt.Sprintf("theme-%d", i)
+			if _, err := st.Update(func(s *Settings) error { s.Theme = theme; return nil }); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for reloading := true; reloading; {
+		select {
+		case <-done:
+			reloading = false
+		default:
+		}
+		if _, err := st.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := fmt.Sprintf("theme-%d", updates)
+	if got := st.Get().Theme; got != want {
+		t.Errorf("Get().Theme = %q, want %q: Reload вернул устаревшие настройки", got, want)
+	}
+}
+
+func TestStoreFirstLaunch(t *testing.T) {
+	st := newTestStore(t)
+	if !st.FirstLaunch() {
+		t.Error("FirstLaunch() = false без settings.json")
+	}
+	// Второй запуск: файл уже создан первым
+	if NewStore().FirstLaunch() {
+		t.Error("FirstLaunch() = true при существующем settings.json")
+	}
+}
@@ -0,0 +1,298 @@
+package app_settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"lce/backend/modules/data_dir"
+)
+
+// exportFormat - метка файла экспорта, чтобы не принять за настройки посторонний JSON.
+const exportFormat = "lce-settings"
+
+// machineFields - настройки, относящиеся к этому компьютеру: они не экспортируются,
+// а при импорте и сбросе сохраняются текущие значения.
+var machineFields = map[string]bool{
+	"version": true,
+	"windows": true,
+}
+
+// legacyFields повторяют installs и active_install_id и при импорте выводятся из них.
+var legacyFields = map[string]bool{
+	"game_path": true,
+	"all_paths": true,
+}
+
+// SettingsExport - содержимое файла экспорта настроек.
+type SettingsExport struct {
+	Format     string                     `json:"format"`
+	Version    int                        `json:"version"`
+	ExportedAt time.Time                  `json:"exported_at"`
+	Settings   map[string]json.RawMessage `json:"settings"`
+}
+
+// SettingChange - отличие импортируемой настройки от текущей.
+type SettingChange struct {
+	Field           string `json:"field"`
+	Current         any    `json:"current"`
+	Imported        any    `json:"imported"`
+	RequiresRestart bool   `json:"requires_restart"`
+}
+
+// ImportPreview - что изменится при импорте файла.
+type ImportPreview struct {
+	Path       string          `json:"path"`
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Changes    []SettingChange `json:"changes"`
+	Warnings   []string        `json:"warnings"` // например, папки установок, которых нет на этом компьютере
+}
+
+// ExportSettings сохраняет настройки приложения (язык, тему, установки, правила сканирования и т.д.)
+// в файл path. Положение окон не экспортируется.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) ExportSettings(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("не указан файл для экспорта")
+	}
+	out, err := encodeExport(a.store.Get())
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл экспорта: %w", err)
+	}
+	return nil
+}
+
+// encodeExport сериализует настройки в формат файла экспорта.
+func encodeExport(settings Settings) ([]byte, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось сериализовать настройки: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("не удалось сериализовать настройки: %w", err)
+	}
+	for name := range machineFields {
+		delete(fields, name)
+	}
+
+	out, err := json.MarshalIndent(SettingsExport{
+		Format:     exportFormat,
+		Version:    settings.Version,
+		ExportedAt: time.Now(),
+		Settings:   fields,
+	}, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("не удалось сериализовать настройки: %w", err)
+	}
+	return out, nil
+}
+
+// PreviewImport проверяет файл экспорта и возвращает список настроек, которые изменятся при импорте.
+// Текущие настройки не меняются.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) PreviewImport(path string) (ImportPreview, error) {
+	export, imported, err := readSettingsExport(path)
+	if err != nil {
+		return ImportPreview{}, err
+	}
+	current := a.store.Get()
+	merged := mergeImported(current, imported)
+
+	preview := ImportPreview{
+		Path:       path,
+		Version:    export.Version,
+		ExportedAt: export.ExportedAt,
+		Changes:    diffSettings(current, merged),
+		Warnings:   []string{},
+	}
+	for _, inst := range merged.Installs {
+		if _, err := os.Stat(inst.Path); err != nil {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("папка установки '%s' не найдена на этом компьютере", inst.Path))
+		}
+	}
+	return preview, nil
+}
+
+// ImportSettings заменяет настройки приложения настройками из файла экспорта.
+// Перед заменой текущие настройки сохраняются в папку резервных копий; путь к копии возвращается.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) ImportSettings(path string) (string, error) {
+	_, imported, err := readSettingsExport(path)
+	if err != nil {
+		return "", err
+	}
+	backupPath, err := backupCurrentSettings(a.store.Get(), "import")
+	if err != nil {
+		return "", err
+	}
+	if _, err := a.store.Update(func(s *Settings) error {
+		*s = mergeImported(*s, imported)
+		return nil
+	}); err != nil {
+		return backupPath, err
+	}
+	fmt.Printf("Настройки импортированы из %s (копия прежних: %s)\n", path, backupPath)
+	return backupPath, nil
+}
+
+// ResetSettings возвращает настройки по умолчанию. Положение окон сохраняется,
+// текущие настройки перед сбросом копируются в папку резервных копий; путь к копии возвращается.
+// Эта функция привязана к фронтенду Wails.
+func (a *AppSettings) ResetSettings() (string, error) {
+	backupPath, err := backupCurrentSettings(a.store.Get(), "reset")
+	if err != nil {
+		return "", err
+	}
+	if _, err := a.store.Update(func(s *Settings) error {
+		*s = mergeImported(*s, DefaultSettings())
+		return nil
+	}); err != nil {
+		return backupPath, err
+	}
+	fmt.Printf("Настройки сброшены (копия прежних: %s)\n", backupPath)
+	return backupPath, nil
+}
+
+// readSettingsExport читает файл экспорта, обновляет его миграциями до текущей версии
+// и проверяет значения по реестру полей.
+func readSettingsExport(path string) (SettingsExport, Settings, error) {
+	var export SettingsExport
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return export, Settings{}, fmt.Errorf("не удалось прочитать файл импорта: %w", err)
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return export, Settings{}, fmt.Errorf("файл импорта повреждён: %w", err)
+	}
+	if export.Format != exportFormat || export.Settings == nil {
+		return export, Settings{}, fmt.Errorf("файл не является экспортом настроек LCE")
+	}
+	if export.Version > CurrentSettingsVersion {
+		return export, Settings{}, fmt.Errorf("файл экспортирован более новой версией приложения (формат %d, поддерживается %d)",
+			export.Version, CurrentSettingsVersion)
+	}
+
+	// Версия файла экспорта задаёт, какие миграции нужны
+	raw := make(map[string]json.RawMessage, len(export.Settings)+1)
+	for name, value := range export.Settings {
+		if !machineFields[name] {
+			raw[name] = value
+		}
+	}
+	raw["version"], _ = json.Marshal(export.Version)
+	if err := validateFields(raw); err != nil {
+		return export, Settings{}, err
+	}
+	body, err := json.Marshal(raw)
+	if err != nil {
+		return export, Settings{}, fmt.Errorf("файл импорта повреждён: %w", err)
+	}
+	imported, raw, err := decodeSettings(body)
+	if err != nil {
+		return export, Settings{}, fmt.Errorf("файл импорта повреждён: %w", err)
+	}
+	migrateSettings(&imported, raw)
+
+	if err := validateInstalls(imported); err != nil {
+		return export, Settings{}, err
+	}
+	return export, imported, nil
+}
+
+// validateFields проверяет значения из файла через реестр полей, как при изменении из интерфейса.
+func validateFields(raw map[string]json.RawMessage) error {
+	values := make(map[string]any)
+	for _, f := range settingsFields {
+		data, ok := raw[f.Name]
+		if !ok || f.ReadOnly || legacyFields[f.Name] {
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("файл импорта повреждён: %w", err)
+		}
+		values[f.Name] = v
+	}
+	check := DefaultSettings()
+	_, err := applyFields(&check, values)
+	return err
+}
+
+// validateInstalls проверяет установки: через UpdateSettings они не меняются, и реестр их не проверяет.
+func validateInstalls(s Settings) error {
+	var errs []FieldError
+	seen := make(map[string]bool)
+	for i, inst := range s.Installs {
+		switch {
+		case inst.ID == "" || strings.TrimSpace(inst.Path) == "":
+			errs = append(errs, FieldError{Field: "installs", Message: fmt.Sprintf("установка %d: не указан идентификатор или путь", i+1)})
+		case seen[inst.ID]:
+			errs = append(errs, FieldError{Field: "installs", Message: fmt.Sprintf("установка %d: повторяющийся идентификатор '%s'", i+1, inst.ID)})
+		}
+		seen[inst.ID] = true
+	}
+	if s.ActiveInstallID != "" && !seen[s.ActiveInstallID] {
+		errs = append(errs, FieldError{Field: "active_install_id", Message: "активная установка отсутствует в списке"})
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// mergeImported возвращает импортированные настройки с сохранёнными настройками этого компьютера.
+func mergeImported(current, imported Settings) Settings {
+	merged := imported.clone()
+	merged.Version = current.Version
+	merged.Windows = current.clone().Windows
+	merged.syncLegacyPaths()
+	merged.normalize()
+	return merged
+}
+
+// diffSettings перечисляет различающиеся поля реестра (кроме относящихся к компьютеру и дублирующих).
+func diffSettings(current, next Settings) []SettingChange {
+	changes := []SettingChange{}
+	for _, f := range settingsFields {
+		if machineFields[f.Name] || legacyFields[f.Name] {
+			continue
+		}
+		was, now := f.get(&current), f.get(&next)
+		wasJSON, _ := json.Marshal(was)
+		nowJSON, _ := json.Marshal(now)
+		if string(wasJSON) != string(nowJSON) {
+			changes = append(changes, SettingChange{
+				Field:           f.Name,
+				Current:         was,
+				Imported:        now,
+				RequiresRestart: f.RequiresRestart,
+			})
+		}
+	}
+	return changes
+}
+
+// backupCurrentSettings сохраняет настройки в папку резервных копий перед импортом или сбросом.
+// Копия записывается в формате экспорта, поэтому её можно вернуть через ImportSettings.
+func backupCurrentSettings(s Settings, reason string) (string, error) {
+	data, err := encodeExport(s)
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("settings-%s-%s.json", reason, time.Now().Format("20060102-150405"))
+	path, err := data_dir.File(data_dir.Backups, name)
+	if err != nil {
+		return "", fmt.Errorf("не удалось сохранить резервную копию настроек: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("не удалось сохранить резервную копию настроек: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,139 @@
+package app_settings
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	st := newTestStore(t)
+	a := NewAppSettings(st)
+	gameDir := t.TempDir()
+	if _, err := a.UpdateSettings(map[string]any{"theme": "dark", "language": "ru"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.AddInstall(gameDir, "Турнир"); err != nil {
+		t.Fatal(err)
+	}
+	st.SaveWindowState(MainWindow, WindowState{X: 1, Y: 2, Width: 1300, Height: 800})
+
+	exportPath := filepath.Join(t.TempDir(), "lce-settings.json")
+	if err := a.ExportSettings(exportPath); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(exportPath); strings.Contains(string(data), `"windows"`) {
+		t.Error("положение окон попало в экспорт")
+	}
+
+	// «Другой компьютер»: настройки по умолчанию и своё положение окна
+	if _, err := a.ResetSettings(); err != nil {
+		t.Fatal(err)
+	}
+	st.SaveWindowState(MainWindow, WindowState{X: 50, Y: 60, Width: 1000, Height: 700})
+
+	preview, err := a.PreviewImport(exportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := map[string]bool{}
+	for _, c := range preview.Changes {
+		changed[c.Field] = true
+	}
+	for _, field := range []string{"theme", "language", "installs", "active_install_id"} {
+		if !changed[field] {
+			t.Errorf("в предпросмотре нет изменения %s: %+v", field, preview.Changes)
+		}
+	}
+	if changed["windows"] || changed["game_path"] || len(preview.Warnings) != 0 {
+		t.Errorf("предпросмотр = %+v", preview)
+	}
+	if st.Get().Theme != "default" {
+		t.Error("предпросмотр изменил настройки")
+	}
+
+	backup, err := a.ImportSettings(exportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := st.Get()
+	if got.Theme != "dark" || got.Language != "ru" || len(got.Installs) != 1 || got.GamePath != filepath.Clean(gameDir) {
+		t.Errorf("после импорта: %+v", got)
+	}
+	if w, _ := got.Window(MainWindow); w.X != 50 {
+		t.Errorf("импорт заменил положение окна: %+v", w)
+	}
+	// Резервную копию можно импортировать обратно
+	if _, err := a.PreviewImport(backup); err != nil {
+		t.Errorf("резервная копия %s не импортируется: %v", backup, err)
+	}
+}
+
+func TestImportRejectsInvalidFiles(t *testing.T) {
+	a := NewAppSettings(newTestStore(t))
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"чужой JSON":          `{"theme": "dark"}`,
+		"не JSON":             `{"format": "lce-settings",`,
+		"новая версия":        `{"format": "lce-settings", "version": 99, "settings": {}}`,
+		"неверный тип":        `{"format": "lce-settings", "version": 4, "settings": {"width": "wide"}}`,
+		"неверный режим":      `{"format": "lce-settings", "version": 4, "settings": {"watch_mode": "sometimes"}}`,
+		"потерянная активная": `{"format": "lce-settings", "version": 4, "settings": {"installs": [{"id": "a", "path": "C:/W3"}], "active_install_id": "b"}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := a.ImportSettings(path); err == nil {
+			t.Errorf("%s: импорт не вернул ошибку", name)
+		}
+	}
+
+	_, err := a.PreviewImport(filepath.Join(dir, "неверный тип.json"))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Field != "width" {
+		t.Errorf("PreviewImport() error = %v, want ошибку поля width", err)
+	}
+	if st := a.GetSettings(); st.Theme != "default" {
+		t.Errorf("неудачный импорт изменил настройки: %+v", st)
+	}
+}
+
+func TestImportMigratesOldExport(t *testing.T) {
+	a := NewAppSettings(newTestStore(t))
+	path := filepath.Join(t.TempDir(), "old.json")
+	// Версия 2: установок ещё нет, только game_path и all_paths
+	content := `{"format": "lce-settings", "version": 2, "settings": {"game_path": "C:/W3", "all_paths": ["C:/W3", "D:/W3"]}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ImportSettings(path); err != nil {
+		t.Fatal(err)
+	}
+	got := a.GetSettings()
+	if len(got.Installs) != 2 || got.GamePath != filepath.Clean("C:/W3") || got.Version != CurrentSettingsVersion {
+		t.Errorf("после импорта старого файла: %+v", got)
+	}
+}
+
+func TestResetKeepsBackup(t *testing.T) {
+	a := NewAppSettings(newTestStore(t))
+	if _, err := a.UpdateSettings(map[string]any{"theme": "dark"}); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := a.ResetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.GetSettings().Theme != "default" {
+		t.Error("тема не сброшена")
+	}
+	data, err := os.ReadFile(backup)
+	if err != nil || !strings.Contains(string(data), `"dark"`) {
+		t.Errorf("резервная копия %s: %s, %v", backup, data, err)
+	}
+}
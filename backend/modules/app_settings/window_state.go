@@ -0,0 +1,110 @@
+package app_settings
+
+// Имена окон в Settings.Windows.
+const (
+	MainWindow     = "main"
+	SettingsWindow = "settings"
+)
+
+// Сколько окна должно оставаться на экране, чтобы его можно было ухватить мышью.
+const (
+	minVisibleWidth  = 100
+	minVisibleHeight = 50
+)
+
+// WindowState - сохранённые положение и размер окна (в независимых от DPI единицах).
+type WindowState struct {
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Maximized bool   `json:"maximized"`
+	Monitor   string `json:"monitor"` // идентификатор монитора, на котором было окно
+}
+
+// ScreenArea - рабочая область монитора (без панели задач).
+type ScreenArea struct {
+	ID      string
+	X       int
+	Y       int
+	Width   int
+	Height  int
+	Primary bool
+}
+
+// Window возвращает сохранённое состояние окна name.
+func (s Settings) Window(name string) (WindowState, bool) {
+	w, ok := s.Windows[name]
+	return w, ok && w.Width > 0 && w.Height > 0
+}
+
+// SaveWindowState запоминает состояние окна name. Размер главного окна дублируется
+// в Width и Height, которые читает старый код фронтенда.
+func (st *Store) SaveWindowState(name string, state WindowState) error {
+	_, err := st.Update(func(s *Settings) error {
+		if s.Windows == nil {
+			s.Windows = make(map[string]WindowState)
+		}
+		s.Windows[name] = state
+		if name == MainWindow {
+			s.Width = uint32(state.Width)
+			s.Height = uint32(state.Height)
+		}
+		return nil
+	})
+	return err
+}
+
+// FitTo возвращает состояние, при котором окно видно на одном из мониторов.
+// Окно, оставшееся на отключённом мониторе или ушедшее за край, переносится в центр
+// своего монитора (если он подключён) или основного; слишком большое окно уменьшается.
+func (w WindowState) FitTo(screens []ScreenArea) WindowState {
+	if len(screens) == 0 || w.Width <= 0 || w.Height <= 0 {
+		return w
+	}
+
+	for _, screen := range screens {
+		if w.visibleOn(screen) {
+			w.Monitor = screen.ID
+			if w.Width > screen.Width || w.Height > screen.Height {
+				w.shrinkInto(screen)
+			}
+			return w
+		}
+	}
+
+	target := screens[0]
+	for _, screen := range screens {
+		if screen.ID == w.Monitor && w.Monitor != "" {
+			target = screen
+			break
+		}
+		if screen.Primary {
+			target = screen
+		}
+	}
+	w.Monitor = target.ID
+	w.Width = min(w.Width, target.Width)
+	w.Height = min(w.Height, target.Height)
+	w.X = target.X + (target.Width-w.Width)/2
+	w.Y = target.Y + (target.Height-w.Height)/2
+	return w
+}
+
+// visibleOn проверяет, что на мониторе видна достаточная часть окна.
+func (w WindowState) visibleOn(screen ScreenArea) bool {
+	left := max(w.X, screen.X)
+	right := min(w.X+w.Width, screen.X+screen.Width)
+	top := max(w.Y, screen.Y)
+	bottom := min(w.Y+w.Height, screen.Y+screen.Height)
+	return right-left >= min(minVisibleWidth, w.Width) && bottom-top >= min(minVisibleHeight, w.Height) &&
+		w.Y >= screen.Y // заголовок окна не должен уходить за верхний край
+}
+
+// shrinkInto уменьшает окно до размеров монитора и сдвигает его внутрь.
+func (w *WindowState) shrinkInto(screen ScreenArea) {
+	w.Width = min(w.Width, screen.Width)
+	w.Height = min(w.Height, screen.Height)
+	w.X = min(max(w.X, screen.X), screen.X+screen.Width-w.Width)
+	w.Y = min(max(w.Y, screen.Y), screen.Y+screen.Height-w.Height)
+}
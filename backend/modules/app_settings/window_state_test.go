@@ -0,0 +1,86 @@
+package app_settings
+
+import "testing"
+
+func TestWindowStateFitTo(t *testing.T) {
+	primary := ScreenArea{ID: "1", X: 0, Y: 0, Width: 1920, Height: 1040, Primary: true}
+	second := ScreenArea{ID: "2", X: 1920, Y: 0, Width: 1280, Height: 984}
+
+	tests := []struct {
+		name    string
+		state   WindowState
+		screens []ScreenArea
+		want    WindowState
+	}{
+		{
+			name:    "видимое окно не двигается",
+			state:   WindowState{X: 100, Y: 50, Width: 1300, Height: 800, Monitor: "1"},
+			screens: []ScreenArea{primary, second},
+			want:    WindowState{X: 100, Y: 50, Width: 1300, Height: 800, Monitor: "1"},
+		},
+		{
+			name:    "окно на втором мониторе",
+			state:   WindowState{X: 2000, Y: 100, Width: 800, Height: 600, Monitor: "2"},
+			screens: []ScreenArea{primary, second},
+			want:    WindowState{X: 2000, Y: 100, Width: 800, Height: 600, Monitor: "2"},
+		},
+		{
+			name:    "второй монитор отключён",
+			state:   WindowState{X: 2000, Y: 100, Width: 800, Height: 600, Monitor: "2", Maximized: true},
+			screens: []ScreenArea{primary},
+			want:    WindowState{X: 560, Y: 220, Width: 800, Height: 600, Monitor: "1", Maximized: true},
+		},
+		{
+			name:    "заголовок за верхним краем",
+			state:   WindowState{X: 100, Y: -300, Width: 800, Height: 600, Monitor: "1"},
+			screens: []ScreenArea{primary},
+			want:    WindowState{X: 560, Y: 220, Width: 800, Height: 600, Monitor: "1"},
+		},
+		{
+			name:    "окно больше монитора уменьшается",
+			state:   WindowState{X: 1900, Y: 0, Width: 1600, Height: 1200, Monitor: "2"},
+			screens: []ScreenArea{primary, second},
+			want:    WindowState{X: 1920, Y: 0, Width: 1280, Height: 984, Monitor: "2"},
+		},
+		{
+			name:    "монитор сохранён, но окно вне его - центр того же монитора",
+			state:   WindowState{X: 9000, Y: 9000, Width: 800, Height: 600, Monitor: "2"},
+			screens: []ScreenArea{primary, second},
+			want:    WindowState{X: 2160, Y: 192, Width: 800, Height: 600, Monitor: "2"},
+		},
+		{
+			name:  "мониторы неизвестны",
+			state: WindowState{X: -5000, Y: 0, Width: 800, Height: 600},
+			want:  WindowState{X: -5000, Y: 0, Width: 800, Height: 600},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.FitTo(tt.screens); got != tt.want {
+				t.Errorf("FitTo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveWindowState(t *testing.T) {
+	st := newTestStore(t)
+	state := WindowState{X: 10, Y: 20, Width: 1400, Height: 850, Monitor: "1"}
+	if err := st.SaveWindowState(MainWindow, state); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := loaded.Window(MainWindow); !ok || got != state {
+		t.Errorf("Window(main) = %+v, %v; want %+v", got, ok, state)
+	}
+	if loaded.Width != 1400 || loaded.Height != 850 {
+		t.Errorf("Width/Height = %d/%d, want размер главного окна", loaded.Width, loaded.Height)
+	}
+	if _, ok := loaded.Window(SettingsWindow); ok {
+		t.Error("состояние окна настроек появилось без сохранения")
+	}
+}
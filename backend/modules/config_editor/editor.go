@@ -2,36 +2,82 @@ package config_editor
 
 import (
 	"fmt"
+	"log"
+	"path/filepath"
 	"strings"
 	"sync"
 
 	"lce/backend/modules/app_settings"
 )
 
+// WriteRecorder получает содержимое конфига перед каждым сохранением,
+// чтобы вотчер мог отличить сохранения редактора от внешних изменений (реализуется config_watcher.WriteLog).
+type WriteRecorder interface {
+	Record(path string, data []byte) uint64
+}
+
 type ConfigEditor struct {
-	mu     sync.Mutex
-	config *GameConfig
+	mu       sync.Mutex
+	config   *GameConfig
+	writes   WriteRecorder
+	settings *app_settings.Store
 }
 
-func NewConfigEditor() *ConfigEditor {
+// NewConfigEditor создаёт редактор. Путь к игре берётся из settings.
+// writes может быть nil, если сохранения не нужно отмечать.
+func NewConfigEditor(settings *app_settings.Store, writes WriteRecorder) *ConfigEditor {
 	return &ConfigEditor{
-		config: &GameConfig{},
+		config:   &GameConfig{},
+		writes:   writes,
+		settings: settings,
+	}
+}
+
+// save сохраняет конфиг, отмечая запись в журнале. Вызывается с захваченным mu.
+func (e *ConfigEditor) save() error {
+	if e.writes == nil {
+		return e.config.Save()
 	}
+	return e.config.SaveWith(func(path string, data []byte) {
+		e.writes.Record(path, data)
+	})
 }
 
 // Загрузить конфиг
 func (e *ConfigEditor) LoadConfig() error {
-	settings, err := app_settings.LoadSettings()
-	if err != nil {
-		return fmt.Errorf("ошибка загрузки настроек: %w", err)
-	}
+	gamePath := e.settings.Get().GamePath
 
-	wcDir := settings.GamePath
-	configPath := wcDir + "\\config.lod.ini"
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.config.Load(ConfigPath(gamePath))
+}
+
+// ConfigPath возвращает путь к config.lod.ini в папке игры.
+func ConfigPath(gamePath string) string {
+	return filepath.Join(gamePath, "config.lod.ini")
+}
 
+// SwitchInstall переключает редактор на config.lod.ini другой установки.
+// Если конфиг новой установки не читается, редактор остаётся пустым,
+// чтобы изменения не записались в конфиг предыдущей установки.
+// Пустой gamePath отвязывает редактор от конфига: активной установки больше нет.
+func (e *ConfigEditor) SwitchInstall(gamePath string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	return e.config.Load(configPath)
+
+	cfg := &GameConfig{}
+	if gamePath == "" {
+		e.config = cfg
+		log.Println("🔁 Config editor detached: no active install")
+		return nil
+	}
+	err := cfg.Load(ConfigPath(gamePath))
+	e.config = cfg
+	if err != nil {
+		return fmt.Errorf("не удалось загрузить конфиг установки '%s': %w", gamePath, err)
+	}
+	log.Println("🔁 Config editor switched to:", cfg.Path())
+	return nil
 }
 
 // Проверить наличие
@@ -57,15 +103,15 @@ func (e *ConfigEditor) SetConfigValue(section, option, value string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.config.Set(section, option, value)
-	return e.config.Save()
+	return e.save()
 }
 
 // Перезагрузить
 func (e *ConfigEditor) ReloadConfig() error {
+	// Загружаем под mu, как LoadConfig: иначе SwitchInstall успел бы сменить файл между чтением пути и загрузкой
 	e.mu.Lock()
-	path := e.config.Path()
-	e.mu.Unlock()
-	return e.config.Load(path)
+	defer e.mu.Unlock()
+	return e.config.Load(e.config.Path())
 }
 
 // Получить значение как Hotkey
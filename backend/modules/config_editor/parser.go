@@ -1,7 +1,9 @@
 package config_editor
 
 import (
+	"bytes"
 	"log"
+	"os"
 
 	"gopkg.in/ini.v1"
 )
@@ -43,12 +45,25 @@ func (c *GameConfig) Set(section, key, value string) {
 
 // Сохранить обратно в файл
 func (c *GameConfig) Save() error {
+	return c.SaveWith(nil)
+}
+
+// SaveWith сохраняет файл, предварительно передав готовое содержимое в beforeWrite
+// (например, чтобы вотчер узнал своё сохранение).
+func (c *GameConfig) SaveWith(beforeWrite func(path string, data []byte)) error {
 	if c.file == nil || c.path == "" {
 		log.Println("⚠ Save skipped: file or path is nil")
 		return nil
 	}
+	var buf bytes.Buffer
+	if _, err := c.file.WriteTo(&buf); err != nil {
+		return err
+	}
+	if beforeWrite != nil {
+		beforeWrite(c.path, buf.Bytes())
+	}
 	log.Println("💾 Saving INI to:", c.path)
-	return c.file.SaveTo(c.path)
+	return os.WriteFile(c.path, buf.Bytes(), 0666)
 }
 
 func (c *GameConfig) Path() string {
@@ -0,0 +1,188 @@
+package config_watcher
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Имена целей наблюдения, регистрируемых приложением.
+const (
+	TargetGameConfig    = "game-config"    // config.lod.ini активной установки
+	TargetThemes        = "themes"         // папка с темами
+	TargetLocales       = "locales"        // папка с переводами
+	TargetLocaleSources = "locale-sources" // папка locales в режиме переводчика
+	TargetSettings      = "settings"       // settings.json приложения
+)
+
+// События об изменении файлов целей (данные: FileChange).
+const (
+	EventConfigChanged = "config-changed"
+	EventThemeChanged  = "theme-changed"
+	EventLocaleChanged = "locale-changed"
+)
+
+// Hub следит за несколькими именованными целями, у каждой свой интервал и своё событие.
+type Hub struct {
+	events EventEmitter
+	writes *WriteLog
+	detect func(path string) string // определение ненадёжных файловых систем для ModeAuto
+
+	mu           sync.Mutex
+	targets      map[string]*targetWatcher
+	mode         Mode          // режим для целей, у которых он не задан
+	pollInterval time.Duration // интервал опроса для целей, у которых он не задан
+}
+
+// NewHub создаёт хаб. События отправляются через events (обычно app.Event).
+// По журналу writes изменения, записанные самим приложением, помечаются как внутренние;
+// nil - все изменения считаются внешними.
+func NewHub(events EventEmitter, writes *WriteLog) *Hub {
+	if writes == nil {
+		writes = NewWriteLog()
+	}
+	return &Hub{
+		events:       events,
+		writes:       writes,
+		detect:       unreliableReason,
+		targets:      make(map[string]*targetWatcher),
+		mode:         ModeAuto,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// SetMode задаёт способ отслеживания и интервал опроса для целей, у которых они не указаны явно,
+// и перезапускает такие цели. interval <= 0 означает интервал по умолчанию.
+func (h *Hub) SetMode(mode Mode, interval time.Duration) error {
+	if err := mode.Validate(); err != nil {
+		return err
+	}
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	interval = max(interval, minPollInterval)
+
+	h.mu.Lock()
+	if h.mode == mode && h.pollInterval == interval {
+		h.mu.Unlock()
+		return nil
+	}
+	h.mode, h.pollInterval = mode, interval
+	var restart []Target
+	for _, tw := range h.targets {
+		if t, ok := tw.requestedTarget(); ok {
+			restart = append(restart, t)
+		}
+	}
+	h.mu.Unlock()
+
+	var errs []error
+	for _, t := range restart {
+		if err := h.Watch(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Watch начинает наблюдение за целью. Если цель с таким именем уже есть и её параметры
+// не изменились, ничего не происходит; иначе прежнее наблюдение заменяется новым.
+func (h *Hub) Watch(t Target) error {
+	if t.Name == "" {
+		return fmt.Errorf("не указано имя цели наблюдения")
+	}
+	if t.Path == "" {
+		return fmt.Errorf("не указан путь для цели '%s'", t.Name)
+	}
+	requested := t
+	t.Path = filepath.Clean(t.Path)
+	if t.Debounce <= 0 {
+		t.Debounce = defaultDebounceMs * time.Millisecond
+	}
+
+	h.mu.Lock()
+	if t.Mode == "" {
+		t.Mode = h.mode
+	}
+	if t.PollInterval <= 0 {
+		t.PollInterval = h.pollInterval
+	}
+	h.mu.Unlock()
+	if err := t.Mode.Validate(); err != nil {
+		return err
+	}
+
+	tw := h.target(t.Name)
+	if err := tw.start(t); err != nil {
+		return err
+	}
+	tw.setRequested(requested)
+	return nil
+}
+
+// Unwatch останавливает наблюдение за целью. Состояние цели сохраняется для Status.
+func (h *Hub) Unwatch(name string) {
+	h.mu.Lock()
+	tw := h.targets[name]
+	h.mu.Unlock()
+	if tw != nil {
+		tw.halt()
+	}
+}
+
+// Status возвращает состояние цели. Для незарегистрированной цели возвращается пустое состояние.
+func (h *Hub) Status(name string) Status {
+	h.mu.Lock()
+	tw := h.targets[name]
+	h.mu.Unlock()
+	if tw == nil {
+		return Status{Name: name}
+	}
+	return tw.getStatus()
+}
+
+// Statuses возвращает состояния всех целей, отсортированные по имени.
+func (h *Hub) Statuses() []Status {
+	h.mu.Lock()
+	names := make([]string, 0, len(h.targets))
+	for name := range h.targets {
+		names = append(names, name)
+	}
+	h.mu.Unlock()
+
+	sort.Strings(names)
+	statuses := make([]Status, 0, len(names))
+	for _, name := range names {
+		statuses = append(statuses, h.Status(name))
+	}
+	return statuses
+}
+
+// Close останавливает наблюдение за всеми целями.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	targets := make([]*targetWatcher, 0, len(h.targets))
+	for _, tw := range h.targets {
+		targets = append(targets, tw)
+	}
+	h.mu.Unlock()
+
+	for _, tw := range targets {
+		tw.halt()
+	}
+}
+
+// target возвращает вотчер цели, создавая его при первом обращении.
+func (h *Hub) target(name string) *targetWatcher {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	tw, ok := h.targets[name]
+	if !ok {
+		tw = &targetWatcher{events: h.events, writes: h.writes, detect: h.detect, status: Status{Name: name}}
+		h.targets[name] = tw
+	}
+	return tw
+}
@@ -0,0 +1,109 @@
+package config_watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHubDirTarget(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "dark.json"), `{"bg":"#000"}`)
+
+	events := make(chanEmitter, 16)
+	hub := NewHub(events, nil)
+	t.Cleanup(hub.Close)
+	if err := hub.Watch(Target{
+		Name:     TargetThemes,
+		Path:     dir,
+		Dir:      true,
+		Pattern:  "*.json",
+		Debounce: testDebounceMs * time.Millisecond,
+		Event:    EventThemeChanged,
+	}); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	// Изменение существующего файла
+	writeFile(t, filepath.Join(dir, "dark.json"), `{"bg":"#111"}`)
+	if got := nextEvent(t, events); got.Target != TargetThemes || got.Path != filepath.Join(dir, "dark.json") || got.Removed {
+		t.Errorf("событие %+v, want изменение dark.json", got)
+	}
+
+	// Файлы, не подходящие под шаблон, игнорируются
+	writeFile(t, filepath.Join(dir, "notes.txt"), "x")
+	expectEvents(t, events, 0)
+
+	// Новый файл
+	writeFile(t, filepath.Join(dir, "light.json"), `{"bg":"#fff"}`)
+	if got := nextEvent(t, events); got.Path != filepath.Join(dir, "light.json") || got.Hash == "" {
+		t.Errorf("событие %+v, want новый light.json", got)
+	}
+
+	// Удаление файла из папки сообщается
+	if err := os.Remove(filepath.Join(dir, "dark.json")); err != nil {
+		t.Fatal(err)
+	}
+	if got := nextEvent(t, events); !got.Removed || got.Path != filepath.Join(dir, "dark.json") {
+		t.Errorf("событие %+v, want удаление dark.json", got)
+	}
+}
+
+func TestHubTargetsAreIndependent(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, "config.lod.ini")
+	settingsDir := filepath.Join(root, "settings")
+	if err := os.Mkdir(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settingsPath := filepath.Join(settingsDir, "settings.json")
+	writeFile(t, configPath, "[Main]\n")
+	writeFile(t, settingsPath, "{}")
+
+	events := make(chanEmitter, 16)
+	hub := NewHub(events, nil)
+	t.Cleanup(hub.Close)
+
+	reloaded := make(chan FileChange, 4)
+	targets := []Target{
+		{Name: TargetGameConfig, Path: configPath, Debounce: testDebounceMs * time.Millisecond, Event: EventConfigChanged},
+		// Без события для фронтенда, только обработчик в Go; интервал заметно длиннее
+		{Name: TargetSettings, Path: settingsPath, Debounce: 4 * testDebounceMs * time.Millisecond,
+			OnChange: func(c FileChange) { reloaded <- c }},
+	}
+	for _, target := range targets {
+		if err := hub.Watch(target); err != nil {
+			t.Fatalf("Watch(%s): %v", target.Name, err)
+		}
+	}
+
+	writeFile(t, settingsPath, `{"theme":"dark"}`)
+	writeFile(t, configPath, "[Main]\nkey=1\n")
+
+	// Конфиг с коротким интервалом приходит первым и не задерживается настройками
+	if got := nextEvent(t, events); got.Target != TargetGameConfig {
+		t.Errorf("событие %+v, want изменение конфига", got)
+	}
+	select {
+	case got := <-reloaded:
+		if got.Target != TargetSettings || got.Path != settingsPath {
+			t.Errorf("OnChange(%+v), want изменение настроек", got)
+		}
+	case <-time.After(20 * testDebounceMs * time.Millisecond):
+		t.Fatal("OnChange для настроек не вызван")
+	}
+	expectEvents(t, events, 0) // у цели настроек нет события
+
+	statuses := hub.Statuses()
+	if len(statuses) != 2 || statuses[0].Name != TargetGameConfig || statuses[1].Name != TargetSettings {
+		t.Fatalf("Statuses() = %+v", statuses)
+	}
+	hub.Unwatch(TargetSettings)
+	if st := hub.Status(TargetSettings); st.Watching || st.LastChange == nil {
+		t.Errorf("Status(settings) после Unwatch = %+v", st)
+	}
+	if st := hub.Status(TargetGameConfig); !st.Watching {
+		t.Errorf("Status(game-config) = %+v, want наблюдается", st)
+	}
+}
@@ -0,0 +1,110 @@
+package config_watcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStopWithoutStart(t *testing.T) {
+	cw := New(NewHub(make(chanEmitter, 1), nil))
+	cw.StopWatching()
+	cw.StopWatching()
+	if st := cw.Status(); st.Watching {
+		t.Errorf("Status() = %+v, want не наблюдает", st)
+	}
+}
+
+func TestStartIsIdempotent(t *testing.T) {
+	cw, _, path := startWatcher(t)
+	first := cw.Status()
+
+	if err := cw.StartWatching(path, testDebounceMs); err != nil {
+		t.Fatalf("повторный StartWatching: %v", err)
+	}
+	if got := cw.Status(); !got.Since.Equal(first.Since) {
+		t.Errorf("повторный запуск перезапустил наблюдение: since %v -> %v", first.Since, got.Since)
+	}
+
+	cw.StopWatching()
+	cw.StopWatching()
+	if st := cw.Status(); st.Watching || st.Path != path {
+		t.Errorf("Status() после остановки = %+v", st)
+	}
+}
+
+func TestStatusReportsLastChange(t *testing.T) {
+	cw, events, path := startWatcher(t)
+
+	st := cw.Status()
+	if !st.Watching || st.Path != path || st.Since.IsZero() || st.LastChange != nil {
+		t.Fatalf("Status() до изменений = %+v", st)
+	}
+
+	writeFile(t, path, "[Main]\nkey=2\n")
+	change := nextEvent(t, events)
+
+	st = cw.Status()
+	if st.LastChange == nil || *st.LastChange != change || st.LastEvent.IsZero() {
+		t.Errorf("Status() = %+v, want последнее изменение %+v", st, change)
+	}
+}
+
+func TestStartErrorInStatus(t *testing.T) {
+	cw := New(NewHub(make(chanEmitter, 1), nil))
+	path := filepath.Join(t.TempDir(), "missing", "config.lod.ini")
+
+	if err := cw.StartWatching(path, testDebounceMs); err == nil {
+		t.Fatal("StartWatching для несуществующей папки не вернул ошибку")
+	}
+	if st := cw.Status(); st.Watching || st.Error == "" {
+		t.Errorf("Status() = %+v, want ошибку запуска", st)
+	}
+}
+
+// TestConcurrentLifecycle запускается с -race: одновременные запуски, остановки,
+// переключения и чтение состояния не должны приводить к гонкам и утечке горутин.
+func TestConcurrentLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for i := 0; i < 3; i++ {
+		sub := filepath.Join(dir, fmt.Sprint(i))
+		if err := os.Mkdir(sub, 0755); err != nil {
+			t.Fatal(err)
+		}
+		p := filepath.Join(sub, "config.lod.ini")
+		writeFile(t, p, "[Main]\n")
+		paths = append(paths, p)
+	}
+
+	cw := New(NewHub(make(chanEmitter, 1024), nil))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				p := paths[(g+i)%len(paths)]
+				switch i % 4 {
+				case 0:
+					_ = cw.StartWatching(p, testDebounceMs)
+				case 1:
+					_ = cw.SwitchInstall(p, true)
+				case 2:
+					cw.Status()
+					writeFile(t, p, fmt.Sprintf("[Main]\nkey=%d\n", i))
+				case 3:
+					cw.StopWatching()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	cw.StopWatching()
+	if st := cw.Status(); st.Watching {
+		t.Errorf("Status() после остановки = %+v", st)
+	}
+}
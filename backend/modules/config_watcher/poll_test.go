@@ -0,0 +1,237 @@
+package config_watcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testPollInterval = 20 * time.Millisecond
+
+// pollHub создаёт хаб, который считает все пути сетевыми, и возвращает его вместе с каналом событий.
+func pollHub(t *testing.T) (*Hub, chanEmitter) {
+	t.Helper()
+	events := make(chanEmitter, 16)
+	hub := NewHub(events, nil)
+	hub.detect = func(string) string { return "SMB" }
+	if err := hub.SetMode(ModeAuto, testPollInterval); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(hub.Close)
+	return hub, events
+}
+
+func TestPollingFileTarget(t *testing.T) {
+	hub, events := pollHub(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.lod.ini")
+	writeFile(t, path, "[Main]\nkey=1\n")
+
+	writes := hub.writes
+	cw := New(hub)
+	if err := cw.StartWatching(path, testDebounceMs); err != nil {
+		t.Fatal(err)
+	}
+	if st := cw.Status(); st.Mode != ModePoll || st.ModeReason == "" {
+		t.Fatalf("Status() = %+v, want опрос с причиной", st)
+	}
+
+	// Внешняя правка
+	writeFile(t, path, "[Main]\nkey=2\n")
+	if got := nextEvent(t, events); got.Internal || got.Path != path || got.Target != TargetGameConfig {
+		t.Errorf("событие %+v, want внешнее изменение", got)
+	}
+
+	// Своё сохранение помечается так же, как при уведомлениях ОС
+	gen := writes.Record(path, []byte("[Main]\nkey=3\n"))
+	writeFile(t, path, "[Main]\nkey=3\n")
+	if got := nextEvent(t, events); !got.Internal || got.Generation != gen {
+		t.Errorf("событие %+v, want внутреннее с generation %d", got, gen)
+	}
+
+	// Смена mtime без изменения содержимого не сообщается
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, 0)
+}
+
+func TestPollingDetectsSameSizeEdit(t *testing.T) {
+	hub, events := pollHub(t)
+	path := filepath.Join(t.TempDir(), "config.lod.ini")
+	writeFile(t, path, "[Main]\nkey=1\n")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hub.Watch(Target{Name: TargetGameConfig, Path: path, Debounce: testDebounceMs * time.Millisecond, Event: EventConfigChanged}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Та же длина и тот же mtime - так выглядит правка на диске с грубым временем изменения
+	writeFile(t, path, "[Main]\nkey=9\n")
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-events:
+		if got.Path != path {
+			t.Errorf("событие %+v", got)
+		}
+	case <-time.After(2*pollHashEvery*testPollInterval + time.Second):
+		t.Fatal("правка без изменения mtime и размера не обнаружена")
+	}
+}
+
+func TestPollingDirTarget(t *testing.T) {
+	hub, events := pollHub(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "en.json"), `{"a":"b"}`)
+
+	if err := hub.Watch(Target{
+		Name: TargetLocales, Path: dir, Dir: true, Pattern: "*.json",
+		Debounce: testDebounceMs * time.Millisecond, Event: EventLocaleChanged,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "ru.json"), `{"a":"б"}`)
+	if got := nextEvent(t, events); got.Path != filepath.Join(dir, "ru.json") || got.Removed {
+		t.Errorf("событие %+v, want новый ru.json", got)
+	}
+	if err := os.Remove(filepath.Join(dir, "en.json")); err != nil {
+		t.Fatal(err)
+	}
+	if got := nextEvent(t, events); got.Path != filepath.Join(dir, "en.json") || !got.Removed {
+		t.Errorf("событие %+v, want удаление en.json", got)
+	}
+}
+
+func TestSetModeRestartsTargets(t *testing.T) {
+	events := make(chanEmitter, 16)
+	hub := NewHub(events, nil)
+	hub.detect = func(string) string { return "" }
+	t.Cleanup(hub.Close)
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	writeFile(t, path, "{}")
+	if err := hub.Watch(Target{Name: TargetSettings, Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	if st := hub.Status(TargetSettings); st.Mode != ModeNative {
+		t.Fatalf("Status() = %+v, want native", st)
+	}
+
+	if err := hub.SetMode(ModePoll, testPollInterval); err != nil {
+		t.Fatal(err)
+	}
+	if st := hub.Status(TargetSettings); !st.Watching || st.Mode != ModePoll {
+		t.Fatalf("Status() после SetMode = %+v, want опрос", st)
+	}
+
+	// Цель с явно заданным режимом от режима хаба не зависит
+	if err := hub.Watch(Target{Name: TargetSettings, Path: path, Mode: ModeNative}); err != nil {
+		t.Fatal(err)
+	}
+	if err := hub.SetMode(ModeAuto, testPollInterval); err != nil {
+		t.Fatal(err)
+	}
+	if st := hub.Status(TargetSettings); st.Mode != ModeNative {
+		t.Errorf("Status() = %+v, want native", st)
+	}
+
+	if err := hub.SetMode("sometimes", 0); err == nil {
+		t.Error("SetMode с неизвестным режимом не вернул ошибку")
+	}
+}
+
+// Режим наблюдения хранится в настройках, поэтому SetMode вызывается из OnChange цели настроек
+// и перезапускает ту самую цель, в горутине которой выполняется.
+func TestSetModeFromOnChange(t *testing.T) {
+	events := make(chanEmitter, 16)
+	hub := NewHub(events, nil)
+	hub.detect = func(string) string { return "" }
+	t.Cleanup(hub.Close)
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	writeFile(t, path, "{}")
+	applied := make(chan error, 1)
+	err := hub.Watch(Target{
+		Name:     TargetSettings,
+		Path:     path,
+		Debounce: testDebounceMs * time.Millisecond,
+		Event:    "settings-changed",
+		OnChange: func(FileChange) {
+			applied <- hub.SetMode(ModePoll, testPollInterval)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, path, `{"watch_mode": "poll"}`)
+	select {
+	case err := <-applied:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("SetMode из OnChange не вернулся")
+	}
+	nextEvent(t, events)
+	if st := hub.Status(TargetSettings); !st.Watching || st.Mode != ModePoll {
+		t.Fatalf("Status() после SetMode = %+v, want опрос", st)
+	}
+
+	// Новый запуск продолжает сообщать об изменениях
+	writeFile(t, path, `{"watch_mode": "poll", "x": 1}`)
+	select {
+	case <-applied:
+	case <-time.After(2 * time.Second):
+		t.Fatal("изменение после перезапуска не замечено")
+	}
+}
+
+// SetMode извне может остановить цель как раз в тот момент, когда её горутина
+// входит в OnChange, который сам вызывает SetMode.
+func TestSetModeRacesOnChange(t *testing.T) {
+	events := make(chanEmitter, 1024)
+	hub := NewHub(events, nil)
+	hub.detect = func(string) string { return "" }
+	t.Cleanup(hub.Close)
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	writeFile(t, path, "{}")
+	err := hub.Watch(Target{
+		Name:     TargetSettings,
+		Path:     path,
+		Debounce: time.Millisecond,
+		OnChange: func(FileChange) { _ = hub.SetMode(ModePoll, testPollInterval) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		modes := []Mode{ModeNative, ModePoll}
+		for i := 0; i < 100; i++ {
+			writeFile(t, path, fmt.Sprintf(`{"n": %d}`, i))
+			if err := hub.SetMode(modes[i%2], testPollInterval); err != nil {
+				t.Error(err)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("SetMode и OnChange заблокировали друг друга")
+	}
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package config_watcher
+
+import "golang.org/x/sys/unix"
+
+// Файловые системы, на которых inotify не видит изменений, сделанных на другой стороне.
+var unreliableFS = map[uint32]string{
+	unix.CIFS_SUPER_MAGIC: "SMB",
+	unix.SMB_SUPER_MAGIC:  "SMB",
+	unix.SMB2_SUPER_MAGIC: "SMB",
+	unix.NFS_SUPER_MAGIC:  "NFS",
+	unix.FUSE_SUPER_MAGIC: "FUSE",
+	unix.V9FS_MAGIC:       "9P",
+}
+
+// unreliableReason возвращает причину, по которой уведомления ОС для path ненадёжны,
+// или пустую строку.
+func unreliableReason(path string) string {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return ""
+	}
+	return unreliableFS[uint32(st.Type)]
+}
@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+
+package config_watcher
+
+// unreliableReason на остальных системах не определяет ненадёжные файловые системы:
+// опрос можно включить в настройках.
+func unreliableReason(string) string {
+	return ""
+}
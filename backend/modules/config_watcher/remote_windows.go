@@ -0,0 +1,45 @@
+//go:build windows
+
+package config_watcher
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// unreliableReason возвращает причину, по которой уведомления ОС для path ненадёжны,
+// или пустую строку. ReadDirectoryChangesW не сообщает об изменениях, сделанных
+// другими компьютерами на сетевых дисках, и частично не работает под Wine.
+func unreliableReason(path string) string {
+	if underWine() {
+		return "Wine"
+	}
+	if strings.HasPrefix(path, `\\`) {
+		return "сетевой путь"
+	}
+	volume := filepath.VolumeName(path)
+	if volume == "" {
+		return ""
+	}
+	root, err := windows.UTF16PtrFromString(volume + `\`)
+	if err != nil {
+		return ""
+	}
+	if windows.GetDriveType(root) == windows.DRIVE_REMOTE {
+		return "сетевой диск"
+	}
+	return ""
+}
+
+// underWine проверяет, запущено ли приложение под Wine (Wine создаёт ключ Software\Wine).
+func underWine() bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Wine`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	key.Close()
+	return true
+}
@@ -0,0 +1,522 @@
+package config_watcher
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Target описывает, за чем следить: один файл или файлы в папке.
+type Target struct {
+	Name     string        // уникальное имя цели, например TargetThemes
+	Path     string        // путь к файлу или к папке (если Dir)
+	Dir      bool          // следить за всеми файлами папки Path, подходящими под Pattern
+	Pattern  string        // шаблон имени файла для папки, например "*.json"; пусто - все файлы
+	Debounce time.Duration // пауза после последнего события до уведомления; 0 - по умолчанию
+	Event    string        // событие для фронтенда; пусто - событие не отправляется
+
+	Mode         Mode          // способ отслеживания; пусто - режим хаба
+	PollInterval time.Duration // интервал опроса в режиме ModePoll; 0 - интервал хаба
+
+	// OnChange вызывается в горутине вотчера при каждом изменении до отправки события.
+	OnChange func(change FileChange)
+}
+
+// Mode - способ отслеживания изменений.
+type Mode string
+
+const (
+	ModeAuto   Mode = "auto"   // уведомления ОС, а там, где они ненадёжны, - опрос
+	ModeNative Mode = "native" // только уведомления ОС
+	ModePoll   Mode = "poll"   // только опрос
+)
+
+// Validate проверяет, что режим известен.
+func (m Mode) Validate() error {
+	switch m {
+	case ModeAuto, ModeNative, ModePoll:
+		return nil
+	}
+	return fmt.Errorf("неизвестный режим наблюдения '%s'", m)
+}
+
+// spec - сравнимая часть цели: если она не изменилась, повторная регистрация ничего не делает.
+type spec struct {
+	path, pattern, event string
+	dir                  bool
+	debounce             time.Duration
+	mode                 Mode
+	pollInterval         time.Duration
+}
+
+func (t Target) spec() spec {
+	return spec{
+		path: t.Path, pattern: t.Pattern, event: t.Event, dir: t.Dir, debounce: t.Debounce,
+		mode: t.Mode, pollInterval: t.PollInterval,
+	}
+}
+
+// watchDir возвращает папку, на которую оформляется подписка.
+// Для файла это его папка: редакторы часто сохраняют файл через запись во временный файл
+// и переименование, после чего наблюдение за самим файлом теряется.
+func (t Target) watchDir() string {
+	if t.Dir {
+		return t.Path
+	}
+	return filepath.Dir(t.Path)
+}
+
+// matches проверяет, относится ли файл name к цели.
+func (t Target) matches(name string) bool {
+	if !t.Dir {
+		return name == t.Path
+	}
+	if filepath.Dir(name) != t.Path {
+		return false
+	}
+	if t.Pattern == "" {
+		return true
+	}
+	ok, _ := filepath.Match(t.Pattern, filepath.Base(name))
+	return ok
+}
+
+// files возвращает существующие файлы цели.
+func (t Target) files() []string {
+	if !t.Dir {
+		return []string{t.Path}
+	}
+	entries, err := os.ReadDir(t.Path)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, entry := range entries {
+		if name := filepath.Join(t.Path, entry.Name()); !entry.IsDir() && t.matches(name) {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// session - один запуск наблюдения. Горутина run владеет watcher и закрывает done при выходе.
+// Если watcher равен nil, изменения отслеживаются опросом.
+// stopped и notifying меняются вместе под mu: stop по ним решает, можно ли ждать горутину,
+// а notify - можно ли вызывать OnChange.
+type session struct {
+	target  Target
+	watcher *fsnotify.Watcher
+	stop    chan struct{}
+	done    chan struct{}
+
+	mu        sync.Mutex
+	stopped   bool // stop закрыт
+	notifying bool // горутина run сейчас внутри OnChange
+}
+
+// targetWatcher следит за одной целью. Все методы безопасны для вызова из разных горутин.
+// lifecycle упорядочивает запуски и остановки и удерживается, пока горутина вотчера завершается;
+// mu защищает текущий запуск и состояние и берётся горутиной вотчера лишь ненадолго,
+// поэтому ожидание её завершения под lifecycle не приводит к взаимной блокировке.
+type targetWatcher struct {
+	events EventEmitter
+	writes *WriteLog
+	detect func(path string) string // причина ненадёжности уведомлений ОС для пути; пусто - надёжны
+
+	lifecycle sync.Mutex
+
+	mu        sync.Mutex
+	session   *session // текущий запуск; nil - наблюдение остановлено
+	status    Status   // состояние последнего запуска
+	requested Target   // цель в том виде, в каком её передали в Hub.Watch (до подстановки режима хаба)
+}
+
+// setRequested запоминает исходную цель для перезапуска при смене режима хаба.
+func (tw *targetWatcher) setRequested(t Target) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.requested = t
+}
+
+// requestedTarget возвращает исходную цель, если за ней сейчас следят.
+func (tw *targetWatcher) requestedTarget() (Target, bool) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.requested, tw.session != nil
+}
+
+// start начинает наблюдение за целью. Если за ней уже следят с теми же параметрами,
+// ничего не происходит; иначе предыдущее наблюдение останавливается.
+func (tw *targetWatcher) start(t Target) error {
+	tw.lifecycle.Lock()
+	defer tw.lifecycle.Unlock()
+
+	tw.mu.Lock()
+	current := tw.session
+	tw.mu.Unlock()
+	if current != nil && current.target.spec() == t.spec() {
+		return nil
+	}
+	tw.stop()
+
+	w, reason, err := tw.open(t)
+	if err != nil {
+		tw.mu.Lock()
+		tw.status = Status{Name: t.Name, Path: t.Path, Error: err.Error()}
+		tw.mu.Unlock()
+		return fmt.Errorf("не удалось начать наблюдение за %s: %w", t.Path, err)
+	}
+	mode := ModeNative
+	if w == nil {
+		mode = ModePoll
+		log.Printf("Для %s используется опрос раз в %v: %s", t.Path, t.PollInterval, reason)
+	}
+
+	s := &session{
+		target:  t,
+		watcher: w,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	// Запоминаем исходное содержимое, чтобы не сообщать о нём как об изменении
+	states := make(map[string]*fileState)
+	for _, name := range t.files() {
+		state := &fileState{path: name}
+		state.refresh()
+		states[name] = state
+	}
+
+	tw.mu.Lock()
+	tw.session = s
+	tw.status = Status{Name: t.Name, Watching: true, Path: t.Path, Since: time.Now(), Mode: mode, ModeReason: reason}
+	tw.mu.Unlock()
+
+	go tw.run(s, states)
+	log.Printf("👀 Watching %s: %s", t.Name, t.Path)
+	return nil
+}
+
+// open выбирает способ отслеживания цели. Возвращает вотчер fsnotify или nil для опроса
+// вместе с причиной перехода на опрос.
+func (tw *targetWatcher) open(t Target) (*fsnotify.Watcher, string, error) {
+	dir := t.watchDir()
+	if t.Mode == ModePoll {
+		return nil, "опрос включён в настройках", checkDir(dir)
+	}
+	if t.Mode == ModeAuto && tw.detect != nil {
+		if reason := tw.detect(dir); reason != "" {
+			return nil, fmt.Sprintf("уведомления ОС ненадёжны (%s)", reason), checkDir(dir)
+		}
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = w.Add(dir); err != nil {
+			_ = w.Close()
+		}
+	}
+	if err != nil && t.Mode == ModeAuto {
+		// Некоторые FUSE и сетевые файловые системы не поддерживают подписку вовсе
+		if dirErr := checkDir(dir); dirErr != nil {
+			return nil, "", dirErr
+		}
+		return nil, fmt.Sprintf("подписка не поддерживается: %v", err), nil
+	}
+	return w, "", err
+}
+
+// checkDir проверяет, что папка существует.
+func checkDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s не является папкой", dir)
+	}
+	return nil
+}
+
+// halt останавливает наблюдение. Повторный вызов ничего не делает.
+func (tw *targetWatcher) halt() {
+	tw.lifecycle.Lock()
+	defer tw.lifecycle.Unlock()
+	tw.stop()
+}
+
+// stop останавливает текущий запуск и дожидается завершения горутины вотчера.
+// Если запуск сейчас внутри OnChange, ждать нельзя: обработчик мог сам перезапустить
+// цель (например, сменить режим наблюдения из-за правки настроек), и горутина ждала бы себя.
+// Такой запуск завершится сам, как только обработчик вернётся.
+// Вызывается с захваченным lifecycle.
+func (tw *targetWatcher) stop() {
+	tw.mu.Lock()
+	s := tw.session
+	tw.session = nil
+	tw.status.Watching = false
+	tw.mu.Unlock()
+
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.stopped = true
+	close(s.stop)
+	wait := !s.notifying
+	s.mu.Unlock()
+	if wait {
+		<-s.done
+	}
+}
+
+// getStatus возвращает копию состояния.
+func (tw *targetWatcher) getStatus() Status {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	status := tw.status
+	if status.LastChange != nil {
+		change := *status.LastChange
+		status.LastChange = &change
+	}
+	return status
+}
+
+// update меняет состояние, если запуск s ещё текущий.
+func (tw *targetWatcher) update(s *session, f func(status *Status)) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.session == s {
+		f(&tw.status)
+	}
+}
+
+// run обрабатывает события файловой системы. Уведомление отправляется по заднему фронту:
+// через debounce после последнего события серии, и только если содержимое файла изменилось.
+// В режиме опроса события fsnotify заменяет периодическая проверка mtime и размера файлов,
+// дальше изменения проходят тот же путь: задержка, сравнение хеша, уведомление.
+func (tw *targetWatcher) run(s *session, states map[string]*fileState) {
+	w := s.watcher
+	t := s.target
+	defer close(s.done)
+
+	var (
+		fsEvents <-chan fsnotify.Event
+		fsErrors <-chan error
+		pollC    <-chan time.Time
+	)
+	if w != nil {
+		defer w.Close()
+		fsEvents, fsErrors = w.Events, w.Errors
+	} else {
+		pollTicker := time.NewTicker(t.PollInterval)
+		defer pollTicker.Stop()
+		pollC = pollTicker.C
+	}
+
+	dir := t.watchDir()
+	armed := true // подписка на папку активна (в режиме опроса подписки нет, переподписываться не нужно)
+	dirty := make(map[string]struct{})
+	polls := 0
+
+	debounceTimer := time.NewTimer(t.Debounce)
+	debounceTimer.Stop()
+	defer debounceTimer.Stop()
+	var pending <-chan time.Time
+
+	rearmTicker := time.NewTicker(rearmInterval)
+	defer rearmTicker.Stop()
+
+	schedule := func() {
+		debounceTimer.Reset(t.Debounce)
+		pending = debounceTimer.C
+	}
+	// markAll помечает все известные и существующие файлы цели для перепроверки
+	markAll := func() {
+		for name := range states {
+			dirty[name] = struct{}{}
+		}
+		for _, name := range t.files() {
+			dirty[name] = struct{}{}
+		}
+		schedule()
+	}
+
+	// Каналы fsnotify закрываются только при закрытии вотчера, а закрывает его эта горутина,
+	// поэтому выход из цикла возможен лишь по сигналу stop.
+	for {
+		select {
+		case event := <-fsEvents:
+			name := filepath.Clean(event.Name)
+			switch {
+			case t.matches(name) && relevant(event.Op):
+				dirty[name] = struct{}{}
+				schedule()
+			case name == dir && (event.Op.Has(fsnotify.Remove) || event.Op.Has(fsnotify.Rename)):
+				// Папку удалили или переименовали - подписка больше ничего не даст
+				log.Printf("Папка %s пропала, наблюдение будет восстановлено при её появлении", dir)
+				_ = w.Remove(dir)
+				armed = false
+				tw.update(s, func(st *Status) { st.Error = fmt.Sprintf("папка %s недоступна", dir) })
+				markAll()
+			}
+
+		case <-pollC:
+			dirAvailable := checkDir(dir) == nil
+			if dirAvailable != armed {
+				armed = dirAvailable
+				tw.update(s, func(st *Status) {
+					st.Error = ""
+					if !dirAvailable {
+						st.Error = fmt.Sprintf("папка %s недоступна", dir)
+					}
+				})
+			}
+			// Раз в pollHashEvery опросов сверяем и хеши: mtime на сетевых дисках бывает грубым,
+			// и правка, не изменившая размер, иначе осталась бы незамеченной
+			polls++
+			fullCheck := polls%pollHashEvery == 0
+			changed := false
+			for _, name := range pollCandidates(t, states) {
+				if fullCheck || statChanged(states[name], name) {
+					dirty[name] = struct{}{}
+					changed = true
+				}
+			}
+			if changed {
+				schedule()
+			}
+
+		case <-rearmTicker.C:
+			if armed || w == nil {
+				continue
+			}
+			if err := w.Add(dir); err == nil {
+				log.Printf("Наблюдение за %s восстановлено", dir)
+				armed = true
+				tw.update(s, func(st *Status) { st.Error = "" })
+				markAll() // файлы могли появиться, пока подписки не было
+			}
+
+		case <-pending:
+			pending = nil
+			names := make([]string, 0, len(dirty))
+			for name := range dirty {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			clear(dirty)
+			for _, name := range names {
+				if change, ok := refreshFile(t, states, name); ok {
+					tw.notify(s, change)
+				}
+			}
+
+		case err := <-fsErrors:
+			log.Println("Watcher error:", err)
+			tw.update(s, func(st *Status) { st.Error = err.Error() })
+
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// pollCandidates возвращает файлы, которые нужно проверить при опросе: известные и появившиеся.
+func pollCandidates(t Target, states map[string]*fileState) []string {
+	names := t.files()
+	for name := range states {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// statChanged сравнивает mtime и размер файла с последним прочитанным состоянием.
+// Сам файл не читается: хеш проверит refresh после задержки.
+func statChanged(state *fileState, name string) bool {
+	info, err := os.Stat(name)
+	if state == nil {
+		return err == nil // новый файл в папке
+	}
+	if err != nil {
+		return state.exists
+	}
+	return !state.exists || !info.ModTime().Equal(state.modTime) || info.Size() != state.size
+}
+
+// refreshFile перечитывает файл цели и возвращает изменение, если о нём нужно сообщить.
+// Удаление одиночного файла не сообщается (при атомарном сохранении он сразу появится снова),
+// а удаление файла из папки - сообщается: так фронтенд узнаёт, что тема или перевод пропали.
+func refreshFile(t Target, states map[string]*fileState, name string) (FileChange, bool) {
+	state, known := states[name]
+	if !known {
+		state = &fileState{path: name}
+		states[name] = state
+	}
+	existed := state.exists
+	if state.refresh() {
+		return FileChange{Target: t.Name, Path: name, Hash: state.hash}, true
+	}
+	if !state.exists && t.Dir {
+		delete(states, name)
+		if existed {
+			return FileChange{Target: t.Name, Path: name, Removed: true}, true
+		}
+	}
+	return FileChange{}, false
+}
+
+// beginNotify отмечает вход в OnChange. Возвращает false, если запуск уже остановлен:
+// stop тогда ждёт завершения горутины, и вызывать обработчик нельзя - он мог бы
+// перезапустить цель и ждать того, кто ждёт его самого.
+func (s *session) beginNotify() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return false
+	}
+	s.notifying = true
+	return true
+}
+
+// notify помечает изменение как внутреннее или внешнее, обновляет состояние и уведомляет подписчиков.
+func (tw *targetWatcher) notify(s *session, change FileChange) {
+	s.mu.Lock()
+	stopped := s.stopped
+	s.mu.Unlock()
+	if stopped {
+		return // запуск остановлен, пока обрабатывалось предыдущее изменение
+	}
+	if !change.Removed {
+		change.Generation, change.Internal = tw.writes.match(change.Path, change.Hash)
+	}
+	switch {
+	case change.Removed:
+		log.Printf("🗑 %s: file removed: %s", change.Target, change.Path)
+	case change.Internal:
+		log.Printf("💾 %s: file saved by the app: %s", change.Target, change.Path)
+	default:
+		log.Printf("⚡ %s: file changed: %s", change.Target, change.Path)
+	}
+	tw.update(s, func(st *Status) {
+		st.LastEvent = time.Now()
+		st.LastChange = &change
+	})
+	if s.target.OnChange != nil && s.beginNotify() {
+		s.target.OnChange(change)
+		s.mu.Lock()
+		s.notifying = false
+		s.mu.Unlock()
+	}
+	if s.target.Event != "" {
+		tw.events.Emit(s.target.Event, change)
+	}
+}
@@ -1,101 +1,151 @@
 package config_watcher
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"log"
-	"path/filepath"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
-	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
+const (
+	defaultDebounceMs = 200
+	rearmInterval     = time.Second // как часто пытаться снова подписаться на пропавшую папку
+
+	defaultPollInterval = 2 * time.Second
+	minPollInterval     = 100 * time.Millisecond
+	pollHashEvery       = 10 // каждый какой опрос сверять хеши всех файлов
+)
+
+// EventEmitter отправляет события во фронтенд. Реализуется app.Event из Wails.
+type EventEmitter interface {
+	Emit(name string, data ...any)
+}
+
+// Status - состояние наблюдения за целью для фронтенда.
+type Status struct {
+	Name       string      `json:"name"` // имя цели
+	Watching   bool        `json:"watching"`
+	Path       string      `json:"path"`
+	Since      time.Time   `json:"since"`       // когда началось наблюдение
+	LastEvent  time.Time   `json:"last_event"`  // когда последний раз сообщалось об изменении
+	LastChange *FileChange `json:"last_change"` // последнее изменение; nil, если изменений не было
+	Error      string      `json:"error"`       // последняя ошибка наблюдения; пусто, если всё в порядке
+	Mode       Mode        `json:"mode"`        // фактический способ: ModeNative или ModePoll
+	ModeReason string      `json:"mode_reason"` // почему используется опрос
+}
+
+// ConfigWatcher - привязанный к фронтенду сервис наблюдения за config.lod.ini активной установки.
+// Само наблюдение выполняет Hub (цель TargetGameConfig), через него же следят за темами, переводами и настройками.
 type ConfigWatcher struct {
-	app        *application.App
-	filePath   string
-	watcher    *fsnotify.Watcher
-	stop       chan struct{}
-	stopOnce   sync.Once
-	lastEvent  time.Time
-	debounceMs int
+	hub *Hub
+
+	mu         sync.Mutex
+	debounceMs int // интервал последнего запуска, используется при SwitchInstall
 }
 
-// New создаёт пустой вотчер
-func New(app *application.App) *ConfigWatcher {
-	return &ConfigWatcher{
-		app:  app,
-		stop: make(chan struct{}),
-	}
+// New создаёт вотчер конфига поверх хаба.
+func New(hub *Hub) *ConfigWatcher {
+	return &ConfigWatcher{hub: hub, debounceMs: defaultDebounceMs}
 }
 
-// StartWatching — вызывается из фронтенда
+// StartWatching начинает наблюдение за файлом path. Если за этим файлом уже следят
+// с тем же интервалом, ничего не происходит; иначе предыдущее наблюдение останавливается.
+// Вызывается из фронтенда.
 func (cw *ConfigWatcher) StartWatching(path string, debounceMs int) error {
-	// если вотчер уже активен — останавливаем
-	if cw.watcher != nil {
-		cw.StopWatching()
-	}
-
 	if debounceMs <= 0 {
-		debounceMs = 200 // дефолт
+		debounceMs = defaultDebounceMs
 	}
-
-	cw.filePath = path
+	cw.mu.Lock()
 	cw.debounceMs = debounceMs
-	cw.stopOnce = sync.Once{}
-	cw.stop = make(chan struct{})
+	cw.mu.Unlock()
 
-	w, err := fsnotify.NewWatcher()
-	if err != nil {
-		return err
-	}
-	cw.watcher = w
+	return cw.hub.Watch(Target{
+		Name:     TargetGameConfig,
+		Path:     path,
+		Debounce: time.Duration(debounceMs) * time.Millisecond,
+		Event:    EventConfigChanged,
+	})
+}
 
-	dir := filepath.Dir(cw.filePath)
-	if err := w.Add(dir); err != nil {
-		return err
+// StopWatching останавливает наблюдение и дожидается завершения горутины вотчера.
+// Повторный вызов ничего не делает. Вызывается из фронтенда.
+func (cw *ConfigWatcher) StopWatching() {
+	cw.hub.Unwatch(TargetGameConfig)
+}
+
+// Status возвращает состояние наблюдения за конфигом.
+// Вызывается из фронтенда.
+func (cw *ConfigWatcher) Status() Status {
+	return cw.hub.Status(TargetGameConfig)
+}
+
+// Statuses возвращает состояние всех целей наблюдения (конфиг, темы, переводы, настройки).
+// Вызывается из фронтенда.
+func (cw *ConfigWatcher) Statuses() []Status {
+	return cw.hub.Statuses()
+}
+
+// SwitchInstall переключает наблюдение на конфиг другой установки.
+// Если watch == false, наблюдение останавливается.
+func (cw *ConfigWatcher) SwitchInstall(path string, watch bool) error {
+	if !watch {
+		cw.StopWatching()
+		return nil
 	}
+	cw.mu.Lock()
+	debounceMs := cw.debounceMs
+	cw.mu.Unlock()
+	return cw.StartWatching(path, debounceMs)
+}
 
-	go cw.run()
-	return nil
+// fileState - последнее известное содержимое наблюдаемого файла.
+type fileState struct {
+	path    string
+	hash    string // хеш содержимого; пусто, если файла нет
+	exists  bool
+	modTime time.Time // mtime и размер на момент чтения, для режима опроса
+	size    int64
 }
 
-func (cw *ConfigWatcher) run() {
-	for {
-		select {
-		case event := <-cw.watcher.Events:
-			if filepath.Clean(event.Name) == filepath.Clean(cw.filePath) &&
-				(event.Op&fsnotify.Write == fsnotify.Write ||
-					event.Op&fsnotify.Create == fsnotify.Create ||
-					event.Op&fsnotify.Rename == fsnotify.Rename ||
-					event.Op&fsnotify.Chmod == fsnotify.Chmod) {
-
-				now := time.Now()
-				if now.Sub(cw.lastEvent) < time.Duration(cw.debounceMs)*time.Millisecond {
-					continue
-				}
-				cw.lastEvent = now
-
-				log.Println("⚡ Config file changed:", event)
-				cw.app.Event.Emit("config-changed", cw.filePath)
-			}
-
-		case err := <-cw.watcher.Errors:
-			log.Println("Watcher error:", err)
-
-		case <-cw.stop:
-			_ = cw.watcher.Close()
-			return
+// refresh перечитывает файл и возвращает true, если его содержимое изменилось.
+// Если файл прочитать не удалось (его нет или он ещё пишется), состояние сбрасывается
+// в "файла нет", и его появление с любым содержимым считается изменением.
+func (s *fileState) refresh() bool {
+	info, statErr := os.Stat(s.path)
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		changed := s.exists
+		s.hash, s.exists = "", false
+		if changed && !os.IsNotExist(err) {
+			log.Printf("Не удалось прочитать %s: %v", s.path, err)
 		}
+		// Удаление файла само по себе не изменение содержимого: при атомарном сохранении
+		// файл почти сразу появляется снова, и тогда сравнивается уже новое содержимое.
+		return false
+	}
+
+	if statErr == nil {
+		s.modTime, s.size = info.ModTime(), info.Size()
 	}
+	hash := hashContent(data)
+	changed := !s.exists || hash != s.hash
+	s.hash, s.exists = hash, true
+	return changed
 }
 
-// StopWatching — останавливает наблюдение
-func (cw *ConfigWatcher) StopWatching() {
-	cw.stopOnce.Do(func() {
-		close(cw.stop)
-		if cw.watcher != nil {
-			_ = cw.watcher.Close()
-			cw.watcher = nil
-		}
-	})
+// hashContent возвращает SHA-256 содержимого файла.
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// relevant проверяет, может ли событие означать изменение содержимого файла.
+// Chmod (смена атрибутов, антивирусы, индексаторы) содержимое не меняет.
+func relevant(op fsnotify.Op) bool {
+	return op.Has(fsnotify.Write) || op.Has(fsnotify.Create) ||
+		op.Has(fsnotify.Rename) || op.Has(fsnotify.Remove)
 }
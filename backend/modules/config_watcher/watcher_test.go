@@ -0,0 +1,224 @@
+package config_watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testDebounceMs = 50
+
+// chanEmitter пересылает данные событий об изменении файлов в канал.
+type chanEmitter chan FileChange
+
+func (c chanEmitter) Emit(name string, data ...any) {
+	if len(data) == 0 {
+		return
+	}
+	change, _ := data[0].(FileChange)
+	c <- change
+}
+
+// startWatcher создаёт config.lod.ini во временной папке и начинает наблюдение за ним.
+func startWatcher(t *testing.T) (*ConfigWatcher, chanEmitter, string) {
+	return startWatcherWith(t, nil)
+}
+
+func startWatcherWith(t *testing.T, writes *WriteLog) (*ConfigWatcher, chanEmitter, string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "game")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.lod.ini")
+	writeFile(t, path, "[Main]\nkey=1\n")
+
+	events := make(chanEmitter, 16)
+	cw := New(NewHub(events, writes))
+	if err := cw.StartWatching(path, testDebounceMs); err != nil {
+		t.Fatalf("StartWatching: %v", err)
+	}
+	t.Cleanup(cw.StopWatching)
+	return cw, events, path
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// expectEvents ждёт, пока события утихнут, и проверяет их количество.
+func expectEvents(t *testing.T, events chanEmitter, want int) {
+	t.Helper()
+	got := 0
+	quiet := time.After(10 * testDebounceMs * time.Millisecond)
+	for {
+		select {
+		case <-events:
+			got++
+			quiet = time.After(10 * testDebounceMs * time.Millisecond)
+		case <-quiet:
+			if got != want {
+				t.Fatalf("получено событий: %d, want %d", got, want)
+			}
+			return
+		}
+	}
+}
+
+func TestWatcherBurstEmitsOnce(t *testing.T) {
+	_, events, path := startWatcher(t)
+
+	for i := 0; i < 5; i++ {
+		writeFile(t, path, "[Main]\nkey="+string(rune('2'+i))+"\n")
+		time.Sleep(testDebounceMs / 5 * time.Millisecond)
+	}
+	expectEvents(t, events, 1)
+}
+
+func TestWatcherIgnoresUnchangedContent(t *testing.T) {
+	_, events, path := startWatcher(t)
+
+	// Перезапись тем же содержимым и смена прав не меняют файл
+	writeFile(t, path, "[Main]\nkey=1\n")
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, 0)
+}
+
+func TestWatcherAtomicReplace(t *testing.T) {
+	_, events, path := startWatcher(t)
+
+	// Так сохраняют многие редакторы: запись во временный файл и переименование поверх
+	tmp := path + ".tmp"
+	writeFile(t, tmp, "[Main]\nkey=2\n")
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, 1)
+
+	// После замены наблюдение продолжает работать
+	writeFile(t, path, "[Main]\nkey=3\n")
+	expectEvents(t, events, 1)
+}
+
+func TestWatcherRemoveAndRecreate(t *testing.T) {
+	_, events, path := startWatcher(t)
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, 0)
+
+	writeFile(t, path, "[Main]\nkey=1\n")
+	expectEvents(t, events, 1)
+}
+
+func TestWatcherRearmsAfterDirRemoved(t *testing.T) {
+	_, events, path := startWatcher(t)
+	dir := filepath.Dir(path)
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * testDebounceMs * time.Millisecond)
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Файл появляется после восстановления подписки или до него - событие приходит в обоих случаях
+	writeFile(t, path, "[Main]\nkey=2\n")
+
+	select {
+	case got := <-events:
+		if got.Path != path || got.Internal {
+			t.Errorf("событие %+v, want внешнее изменение %q", got, path)
+		}
+	case <-time.After(3 * rearmInterval):
+		t.Fatal("наблюдение не восстановилось после пересоздания папки")
+	}
+}
+
+// nextEvent ждёт одно событие.
+func nextEvent(t *testing.T, events chanEmitter) FileChange {
+	t.Helper()
+	select {
+	case change := <-events:
+		return change
+	case <-time.After(20 * testDebounceMs * time.Millisecond):
+		t.Fatal("событие не пришло")
+		return FileChange{}
+	}
+}
+
+func TestWatcherTagsOwnWrites(t *testing.T) {
+	writes := NewWriteLog()
+	_, events, path := startWatcherWith(t, writes)
+
+	// Сохранение приложения
+	own := []byte("[Main]\nkey=2\n")
+	gen := writes.Record(path, own)
+	writeFile(t, path, string(own))
+	if got := nextEvent(t, events); !got.Internal || got.Generation != gen {
+		t.Errorf("событие %+v, want внутреннее с generation %d", got, gen)
+	}
+
+	// Правка другой программой
+	writeFile(t, path, "[Main]\nkey=3\n")
+	if got := nextEvent(t, events); got.Internal || got.Generation != 0 {
+		t.Errorf("событие %+v, want внешнее", got)
+	}
+
+	// Внешняя правка, вернувшая содержимое прежнего сохранения, не считается своей:
+	// запись уже сопоставлена и забыта
+	writeFile(t, path, string(own))
+	if got := nextEvent(t, events); got.Internal {
+		t.Errorf("событие %+v, want внешнее", got)
+	}
+}
+
+func TestWatcherSeriesOfOwnWrites(t *testing.T) {
+	writes := NewWriteLog()
+	_, events, path := startWatcherWith(t, writes)
+
+	// Несколько быстрых сохранений сливаются в одно событие с номером последнего
+	var last uint64
+	for i := 2; i <= 4; i++ {
+		data := "[Main]\nkey=" + string(rune('0'+i)) + "\n"
+		last = writes.Record(path, []byte(data))
+		writeFile(t, path, data)
+	}
+	if got := nextEvent(t, events); !got.Internal || got.Generation != last {
+		t.Errorf("событие %+v, want внутреннее с generation %d", got, last)
+	}
+	expectEvents(t, events, 0)
+}
+
+func TestWriteLogExpires(t *testing.T) {
+	now := time.Now()
+	l := NewWriteLog()
+	l.now = func() time.Time { return now }
+
+	path := filepath.Join("game", "config.lod.ini")
+	gen := l.Record(path, []byte("a"))
+	if got, ok := l.match(filepath.Join("game", ".", "config.lod.ini"), hashContent([]byte("a"))); !ok || got != gen {
+		t.Errorf("match() = %d, %v, want %d, true", got, ok, gen)
+	}
+
+	// Сохранение, которое вотчер так и не увидел, забывается
+	l.Record(path, []byte("b"))
+	now = now.Add(pendingWriteTTL + time.Second)
+	if _, ok := l.match(path, hashContent([]byte("b"))); ok {
+		t.Error("устаревшая запись сопоставлена")
+	}
+	if len(l.pending) != 0 {
+		t.Errorf("pending = %v, want пусто", l.pending)
+	}
+}
@@ -0,0 +1,101 @@
+package config_watcher
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// pendingWriteTTL - сколько помнить запись приложения. Если за это время вотчер
+// не увидел файл с таким содержимым (запись не удалась или ничего не изменила),
+// запись забывается, чтобы не принять за свою более позднюю внешнюю правку.
+const pendingWriteTTL = 10 * time.Second
+
+// FileChange - данные событий об изменении файлов (EventConfigChanged и других).
+type FileChange struct {
+	Target     string `json:"target"` // имя цели наблюдения
+	Path       string `json:"path"`
+	Hash       string `json:"hash"`       // SHA-256 нового содержимого; пусто, если файл удалён
+	Removed    bool   `json:"removed"`    // файл удалён из наблюдаемой папки
+	Internal   bool   `json:"internal"`   // изменение записано самим приложением
+	Generation uint64 `json:"generation"` // номер записи приложения; 0 для внешних изменений
+}
+
+type pendingWrite struct {
+	hash       string
+	generation uint64
+	at         time.Time
+}
+
+// WriteLog запоминает, что приложение записало в наблюдаемые файлы, чтобы вотчер
+// мог отличить собственные сохранения от внешних изменений.
+// Один журнал разделяют config_editor (пишет) и ConfigWatcher (сверяет).
+type WriteLog struct {
+	mu         sync.Mutex
+	generation uint64
+	pending    map[string][]pendingWrite // ключ - очищенный путь файла
+	now        func() time.Time
+}
+
+// NewWriteLog создаёт пустой журнал записей.
+func NewWriteLog() *WriteLog {
+	return &WriteLog{
+		pending: make(map[string][]pendingWrite),
+		now:     time.Now,
+	}
+}
+
+// Record отмечает, что приложение собирается записать data в файл path, и возвращает номер записи.
+// Вызывается до записи, чтобы вотчер не успел увидеть изменение раньше отметки.
+func (l *WriteLog) Record(path string, data []byte) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+	key := filepath.Clean(path)
+	l.pending[key] = append(l.prune(key), pendingWrite{
+		hash:       hashContent(data),
+		generation: l.generation,
+		at:         l.now(),
+	})
+	return l.generation
+}
+
+// match проверяет, записано ли содержимое с хешем hash самим приложением.
+// Найденная запись и все более ранние записи в этот файл забываются.
+func (l *WriteLog) match(path, hash string) (uint64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := filepath.Clean(path)
+	writes := l.prune(key)
+	// Ищем с конца: при серии сохранений на диске оказывается последнее
+	for i := len(writes) - 1; i >= 0; i-- {
+		if writes[i].hash == hash {
+			gen := writes[i].generation
+			l.pending[key] = writes[i+1:]
+			if len(l.pending[key]) == 0 {
+				delete(l.pending, key)
+			}
+			return gen, true
+		}
+	}
+	return 0, false
+}
+
+// prune удаляет устаревшие записи файла и возвращает оставшиеся. Вызывается с захваченным mu.
+func (l *WriteLog) prune(key string) []pendingWrite {
+	writes := l.pending[key]
+	cutoff := l.now().Add(-pendingWriteTTL)
+	i := 0
+	for i < len(writes) && writes[i].at.Before(cutoff) {
+		i++
+	}
+	writes = writes[i:]
+	if len(writes) == 0 {
+		delete(l.pending, key)
+		return nil
+	}
+	l.pending[key] = writes
+	return writes
+}
@@ -0,0 +1,242 @@
+// Пакет data_dir определяет, где приложение хранит свои файлы.
+//
+// Обычно настройки, резервные копии, профили, журналы и пользовательские переводы лежат
+// в папке пользователя (os.UserConfigDir), а темы - рядом с программой.
+// Переводы, поставляемые с программой, встроены в исполняемый файл. В переносном режиме
+// всё хранится рядом с исполняемым файлом, чтобы приложение можно было запускать с флешки.
+// Переносной режим включается файлом-маркером PortableMarker рядом с программой или флагом --portable.
+package data_dir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// PortableMarker - файл рядом с исполняемым файлом, включающий переносной режим.
+const PortableMarker = "portable.txt"
+
+// PortableFlag - флаг командной строки, включающий переносной режим.
+const PortableFlag = "--portable"
+
+// appDirName - папка приложения внутри os.UserConfigDir.
+const appDirName = "LoD Config Editor"
+
+// portableDataDir - папка с данными рядом с программой в переносном режиме.
+const portableDataDir = "data"
+
+// Kind - вид файлов приложения.
+type Kind string
+
+const (
+	Data     Kind = "data"     // settings.json, кэш сканирования
+	Backups  Kind = "backups"  // резервные копии настроек
+	Profiles Kind = "profiles" // профили настроек игры
+	Logs     Kind = "logs"     // журналы работы
+	Themes   Kind = "themes"   // темы оформления
+	Locales  Kind = "locales"  // пользовательские переводы: дополняют и заменяют встроенные
+)
+
+// Layout - выбранное расположение файлов приложения.
+type Layout struct {
+	Portable bool   `json:"portable"`
+	Reason   string `json:"reason"` // почему выбран этот режим
+	Data     string `json:"data"`   // корень данных: настройки, резервные копии, профили, журналы, переводы
+	Assets   string `json:"assets"` // папка, в которой лежат themes
+}
+
+// Path возвращает папку для файлов вида kind, не создавая её.
+func (l Layout) Path(kind Kind) string {
+	switch kind {
+	case Data:
+		return l.Data
+	case Themes:
+		return filepath.Join(l.Assets, string(kind))
+	default:
+		return filepath.Join(l.Data, string(kind))
+	}
+}
+
+// Options - входные данные для выбора расположения.
+type Options struct {
+	Portable bool   // передан флаг --portable
+	ExeDir   string // папка исполняемого файла; пусто - определить по os.Executable
+	WorkDir  string // рабочая папка; пусто - os.Getwd
+}
+
+var (
+	mu      sync.RWMutex
+	current *Layout
+)
+
+// Setup выбирает расположение файлов по аргументам командной строки и маркеру рядом с программой.
+// Вызывается в начале main до создания сервисов. Если переносной режим запрошен, но папка
+// программы недоступна для записи (например, флешка защищена), используется обычный режим,
+// а причина возвращается в Layout.Reason.
+func Setup(args []string) (Layout, error) {
+	opts := Options{}
+	for _, arg := range args {
+		if arg == PortableFlag || arg == "-portable" {
+			opts.Portable = true
+		}
+	}
+	layout, err := Resolve(opts)
+	if err != nil {
+		return layout, err
+	}
+	Use(layout)
+	return layout, nil
+}
+
+// Use задаёт расположение файлов явно (например, временные папки в тестах).
+func Use(layout Layout) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = &layout
+}
+
+// Current возвращает выбранное расположение. Если Setup ещё не вызывался,
+// расположение определяется по маркеру без учёта аргументов командной строки.
+func Current() Layout {
+	mu.RLock()
+	l := current
+	mu.RUnlock()
+	if l != nil {
+		return *l
+	}
+
+	layout, err := Resolve(Options{})
+	if err != nil {
+		// Без папки пользователя остаётся только рабочая папка
+		wd, _ := os.Getwd()
+		layout = Layout{Reason: err.Error(), Data: wd, Assets: wd}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		current = &layout
+	}
+	return *current
+}
+
+// Path возвращает папку для файлов вида kind в текущем расположении, не создавая её.
+func Path(kind Kind) string {
+	return Current().Path(kind)
+}
+
+// Dir возвращает папку для файлов вида kind, создавая её при необходимости.
+func Dir(kind Kind) (string, error) {
+	dir := Path(kind)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("не удалось создать папку '%s': %w", dir, err)
+	}
+	return dir, nil
+}
+
+// File возвращает путь к файлу name в папке вида kind, создавая папку при необходимости.
+func File(kind Kind, name string) (string, error) {
+	dir, err := Dir(kind)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Resolve выбирает расположение файлов, не запоминая его.
+func Resolve(opts Options) (Layout, error) {
+	exeDir := opts.ExeDir
+	if exeDir == "" {
+		if exe, err := os.Executable(); err == nil {
+			if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+				exe = resolved
+			}
+			exeDir = filepath.Dir(exe)
+		}
+	}
+	workDir := opts.WorkDir
+	if workDir == "" {
+		workDir, _ = os.Getwd()
+	}
+
+	portableReason := ""
+	switch {
+	case opts.Portable:
+		portableReason = "передан флаг " + PortableFlag
+	case exeDir != "" && fileExists(filepath.Join(exeDir, PortableMarker)):
+		portableReason = "найден файл " + PortableMarker
+	}
+
+	var fallbackReason string
+	if portableReason != "" {
+		if exeDir == "" {
+			fallbackReason = "не удалось определить папку программы"
+		} else {
+			data := filepath.Join(exeDir, portableDataDir)
+			if err := checkWritable(data); err != nil {
+				fallbackReason = fmt.Sprintf("папка программы недоступна для записи: %v", err)
+			} else {
+				return Layout{Portable: true, Reason: portableReason, Data: data, Assets: exeDir}, nil
+			}
+		}
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return Layout{}, fmt.Errorf("не удалось получить директорию конфигурации пользователя: %w", err)
+	}
+	layout := Layout{
+		Reason: "обычный режим",
+		Data:   filepath.Join(configDir, appDirName),
+		Assets: assetsDir(exeDir, workDir),
+	}
+	if fallbackReason != "" {
+		layout.Reason = fmt.Sprintf("переносной режим недоступен (%s): %s", portableReason, fallbackReason)
+	}
+	return layout, nil
+}
+
+// assetsDir выбирает папку с темами: рядом с программой, если они там есть
+// (установленное приложение), иначе рабочую папку (запуск из исходников через wails3 dev).
+func assetsDir(exeDir, workDir string) string {
+	if exeDir != "" && dirExists(filepath.Join(exeDir, string(Themes))) {
+		return exeDir
+	}
+	if workDir != "" {
+		return workDir
+	}
+	return exeDir
+}
+
+// checkWritable создаёт dir и проверяет, что в неё можно записать файл.
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".write-test-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// String возвращает краткое описание расположения для журнала.
+func (l Layout) String() string {
+	mode := "обычный"
+	if l.Portable {
+		mode = "переносной"
+	}
+	return fmt.Sprintf("режим: %s (%s), данные: %s, темы: %s", mode, l.Reason, l.Data, l.Assets)
+}
@@ -0,0 +1,109 @@
+package data_dir
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testDirs создаёт папки программы, рабочую и пользовательскую и подставляет последнюю в os.UserConfigDir.
+func testDirs(t *testing.T) (exeDir, workDir, configDir string) {
+	t.Helper()
+	root := t.TempDir()
+	exeDir = filepath.Join(root, "usb", "lce")
+	workDir = filepath.Join(root, "work")
+	configDir = filepath.Join(root, "config")
+	for _, dir := range []string{exeDir, workDir, configDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("APPDATA", configDir)
+	return exeDir, workDir, configDir
+}
+
+func TestResolveDefault(t *testing.T) {
+	exeDir, workDir, configDir := testDirs(t)
+
+	l, err := Resolve(Options{ExeDir: exeDir, WorkDir: workDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Portable || l.Data != filepath.Join(configDir, appDirName) {
+		t.Errorf("Resolve() = %+v, want данные в папке пользователя", l)
+	}
+	// Рядом с программой нет тем - запуск из исходников
+	if l.Path(Themes) != filepath.Join(workDir, "themes") {
+		t.Errorf("Path(Themes) = %s, want в рабочей папке", l.Path(Themes))
+	}
+
+	if err := os.Mkdir(filepath.Join(exeDir, "themes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	l, _ = Resolve(Options{ExeDir: exeDir, WorkDir: workDir})
+	if l.Path(Themes) != filepath.Join(exeDir, "themes") || l.Path(Backups) != filepath.Join(l.Data, "backups") {
+		t.Errorf("Resolve() = %+v, want темы рядом с программой", l)
+	}
+	// Пользовательские переводы всегда в папке данных, встроенные - в программе
+	if l.Path(Locales) != filepath.Join(l.Data, "locales") {
+		t.Errorf("Path(Locales) = %s, want в папке данных", l.Path(Locales))
+	}
+}
+
+func TestResolvePortable(t *testing.T) {
+	exeDir, workDir, _ := testDirs(t)
+
+	l, err := Resolve(Options{Portable: true, ExeDir: exeDir, WorkDir: workDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.Portable || l.Data != filepath.Join(exeDir, portableDataDir) || l.Path(Themes) != filepath.Join(exeDir, "themes") {
+		t.Errorf("Resolve(--portable) = %+v", l)
+	}
+
+	if err := os.WriteFile(filepath.Join(exeDir, PortableMarker), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, _ = Resolve(Options{ExeDir: exeDir, WorkDir: workDir})
+	if !l.Portable || !strings.Contains(l.Reason, PortableMarker) {
+		t.Errorf("Resolve() с маркером = %+v", l)
+	}
+	if entries, _ := os.ReadDir(l.Data); len(entries) != 0 {
+		t.Errorf("после проверки записи остались файлы: %v", entries)
+	}
+}
+
+func TestResolvePortableNotWritable(t *testing.T) {
+	exeDir, workDir, configDir := testDirs(t)
+	// Файл на месте папки данных: создать её нельзя, как на защищённой от записи флешке
+	if err := os.WriteFile(filepath.Join(exeDir, portableDataDir), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := Resolve(Options{Portable: true, ExeDir: exeDir, WorkDir: workDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Portable || l.Data != filepath.Join(configDir, appDirName) || !strings.Contains(l.Reason, "недоступен") {
+		t.Errorf("Resolve() = %+v, want обычный режим с причиной", l)
+	}
+}
+
+func TestUseAndDir(t *testing.T) {
+	dir := t.TempDir()
+	Use(Layout{Data: filepath.Join(dir, "data"), Assets: dir})
+	t.Cleanup(func() { current = nil })
+
+	path, err := File(Backups, "settings.json.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "data", "backups", "settings.json.bak") {
+		t.Errorf("File() = %s", path)
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		t.Errorf("папка резервных копий не создана: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Источники перевода для поля "source" в GetLanguages.
+const (
+	sourceBuiltin = "builtin" // только встроенный файл
+	sourceUser    = "user"    // только файл пользователя
+	sourceMerged  = "builtin+user"
+)
+
+// source - набор файлов <код>.json: встроенные переводы или папка пользователя.
+type source struct {
+	fsys   fs.FS
+	origin string // для сообщений об ошибках
+	user   bool
+}
+
+// catalog - источники переводов по возрастанию приоритета: ключи следующего заменяют ключи предыдущего.
+type catalog []source
+
+// locale - перевод одного языка, собранный из всех источников.
+type locale struct {
+	entries map[string]string
+	builtin bool
+	user    bool
+}
+
+// source возвращает, откуда взят перевод.
+func (l *locale) source() string {
+	switch {
+	case l.builtin && l.user:
+		return sourceMerged
+	case l.user:
+		return sourceUser
+	default:
+		return sourceBuiltin
+	}
+}
+
+// locales читает все переводы каталога. Повреждённые файлы пропускаются с сообщением в журнале.
+func (c catalog) locales() map[string]*locale {
+	locales := make(map[string]*locale)
+	for _, src := range c {
+		entries, err := fs.ReadDir(src.fsys, ".")
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				fmt.Printf("Ошибка чтения переводов '%s': %v\n", src.origin, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			code := strings.TrimSuffix(entry.Name(), ".json")
+			translations, err := readLocaleFile(src.fsys, entry.Name())
+			if err != nil {
+				fmt.Printf("Ошибка чтения перевода '%s' (%s): %v\n", entry.Name(), src.origin, err)
+				continue
+			}
+			l := locales[code]
+			if l == nil {
+				l = &locale{entries: make(map[string]string)}
+				locales[code] = l
+			}
+			for key, value := range translations {
+				l.entries[key] = value
+			}
+			if src.user {
+				l.user = true
+			} else {
+				l.builtin = true
+			}
+		}
+	}
+	return locales
+}
+
+// load читает перевод одного языка из всех источников.
+// Возвращает false, если файла этого языка нет ни в одном источнике.
+func (c catalog) load(code string) (map[string]string, bool, error) {
+	name := code + ".json"
+	if code == "" || strings.ContainsAny(code, `/\`) || !fs.ValidPath(name) {
+		return nil, false, fmt.Errorf("недопустимый код языка '%s'", code)
+	}
+
+	result := make(map[string]string)
+	found := false
+	for _, src := range c {
+		translations, err := readLocaleFile(src.fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("не удалось прочитать перевод '%s' (%s): %w", name, src.origin, err)
+		}
+		for key, value := range translations {
+			result[key] = value
+		}
+		found = true
+	}
+	return result, found, nil
+}
+
+// readLocaleFile читает и разворачивает один файл перевода; разметка подсказок очищается.
+// Нарушения правил (см. validateLocale) здесь не проверяются: о них сообщает ValidateLocales.
+func readLocaleFile(fsys fs.FS, name string) (map[string]string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var jsonValue interface{}
+	if err := json.Unmarshal(data, &jsonValue); err != nil {
+		return nil, fmt.Errorf("не удалось десериализовать JSON: %w", err)
+	}
+	entries := flattenJSON(jsonValue)
+	sanitizeEntries(entries)
+	return entries, nil
+}
+
+// sortedCodes возвращает коды языков по алфавиту.
+func sortedCodes(locales map[string]*locale) []string {
+	codes := make([]string, 0, len(locales))
+	for code := range locales {
+		codes = append(codes, code)
+	}
+	return sortedStrings(codes)
+}
+
+// sortedStrings возвращает отсортированную копию списка.
+func sortedStrings(list []string) []string {
+	sorted := append([]string(nil), list...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,138 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLanguage - последний язык в цепочке запасных и эталон для отчёта о полноте переводов.
+const DefaultLanguage = "en"
+
+// Translations - переводы языка с указанием строк, взятых из запасных языков.
+type Translations struct {
+	Language string            `json:"language"`
+	Chain    []string          `json:"chain"` // найденные языки цепочки, от запрошенного к английскому
+	Entries  map[string]string `json:"entries"`
+	Fallback map[string]string `json:"fallback"` // ключ -> язык, из которого взята строка вместо запрошенного
+
+	collisions map[string][]string // короткие ключи, встречающиеся в нескольких разделах
+}
+
+// languageParents возвращает код и его более общие варианты: "zh-Hant-TW" -> ["zh-Hant-TW", "zh-Hant", "zh"].
+func languageParents(code string) []string {
+	parents := []string{code}
+	for {
+		cut := strings.LastIndexAny(code, "-_")
+		if cut <= 0 {
+			return parents
+		}
+		code = code[:cut]
+		parents = append(parents, code)
+	}
+}
+
+// fallbackChain возвращает цепочку языков для кода: сам код, его более общие варианты
+// и английский, например "es-MX" -> ["es-MX", "es", "en"].
+func fallbackChain(code string) []string {
+	chain := languageParents(code)
+	if chain[len(chain)-1] != DefaultLanguage {
+		chain = append(chain, DefaultLanguage)
+	}
+	return chain
+}
+
+// GetTranslationsWithFallback возвращает переводы языка, дополненные по цепочке запасных языков
+// (например, "es-MX" -> "es" -> "en"), и список ключей, взятых из запасных.
+// Пустая строка считается непереведённой и тоже заменяется запасной.
+// Собранные переводы запоминаются до ClearCache; возвращаемые карты изменять нельзя.
+// Эта функция привязана к фронтенду Wails.
+func (i *I18N) GetTranslationsWithFallback(langCode string) (Translations, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if r, ok := i.cache[langCode]; ok {
+		return r.translations, r.err
+	}
+
+	t, err := resolve(i.catalog(), langCode)
+	if err == nil {
+		reportCollisions(langCode, t.collisions)
+	}
+	if i.cache == nil {
+		i.cache = make(map[string]resolved)
+	}
+	i.cache[langCode] = resolved{translations: t, err: err}
+	return t, err
+}
+
+// resolve собирает переводы языка code по цепочке запасных языков.
+func resolve(c catalog, code string) (Translations, error) {
+	t := Translations{
+		Language: code,
+		Chain:    []string{},
+		Entries:  make(map[string]string),
+		Fallback: make(map[string]string),
+	}
+	chain := fallbackChain(code)
+	layers := make([]map[string]string, len(chain))
+	requested := false
+	for n, lang := range chain {
+		entries, ok, err := c.load(lang)
+		if err != nil {
+			return t, err
+		}
+		if !ok {
+			continue
+		}
+		layers[n] = entries
+		t.Chain = append(t.Chain, lang)
+		// Английский в конце цепочки - только запасной: без файла самого языка или его общего варианта
+		// язык считается отсутствующим
+		if n < len(chain)-1 || lang == code {
+			requested = true
+		}
+	}
+	if !requested {
+		// Если файл не найден, возвращаем пустую карту и ошибку
+		return t, fmt.Errorf("файл перевода для '%s' не найден", code)
+	}
+
+	// Слои накладываются от запрошенного к английскому: запасной язык заполняет только недостающие
+	// ключи, а пустая строка считается непереведённой и уступает непустой.
+	// Короткие ключи подбираются в каждом файле отдельно: раздел строки может отличаться
+	// от английского файла, и без этого короткий ключ стал бы неоднозначным после слияния
+	own := len(languageParents(code))
+	ambiguous := make(map[string]bool) // короткие ключи, неоднозначные в более приоритетном файле
+	t.collisions = make(map[string][]string)
+	for n, entries := range layers {
+		if entries == nil {
+			continue
+		}
+		aliases, collisions := shortKeys(entries)
+		layer := make(map[string]string, len(entries)+len(aliases))
+		for key, value := range entries {
+			layer[key] = value
+		}
+		for short, full := range aliases {
+			if !ambiguous[short] {
+				layer[short] = entries[full]
+			}
+		}
+		for short, keys := range collisions {
+			ambiguous[short] = true
+			if n < own {
+				t.collisions[short] = keys // неоднозначности английского файла сообщаются только для него самого
+			}
+		}
+
+		for key, value := range layer {
+			if current, ok := t.Entries[key]; ok && (current != "" || value == "") {
+				continue
+			}
+			t.Entries[key] = value
+			if chain[n] != code {
+				t.Fallback[key] = chain[n]
+			}
+		}
+	}
+	return t, nil
+}
@@ -1,155 +1,215 @@
 package i18n
 
 import (
-	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"lce/backend/modules/app_settings" // Убедитесь, что путь правильный
+	"lce/backend/modules/data_dir"
 )
 
 // I18N - это структура, которая будет привязана к фронтенду Wails
 type I18N struct {
-	// Здесь можно добавить поля, если они нужны для состояния I18N
+	settings *app_settings.Store // текущий язык хранится в общих настройках приложения
+	shipped  fs.FS               // переводы, встроенные в программу: <код>.json в корне
+	events   EventEmitter        // события режима переводчика; nil - не отправляются
+
+	mu    sync.Mutex
+	cache map[string]resolved // собранные переводы по кодам языков; сбрасывается ClearCache
 }
 
-// NewI18N создает новый экземпляр I18N
-func NewI18N() *I18N {
-	return &I18N{}
+// resolved - результат сборки переводов языка, в том числе неудачной.
+type resolved struct {
+	translations Translations
+	err          error
 }
 
-// GetLanguages возвращает список доступных языков.
-// Он сканирует директорию 'locales', читает JSON-файлы
-// и извлекает код языка (имя файла) и имя языка (из поля "lang_name" в JSON).
-func (i *I18N) GetLanguages() ([]map[string]string, error) { // Изменено на метод
-	var langs []map[string]string
-	localesDir := "locales" // Предполагаем, что директория 'locales' находится в корне проекта
+// NewI18N создает новый экземпляр I18N. shipped - встроенные переводы;
+// файлы из папки пользователя (LocalesDir) дополняют и заменяют их.
+func NewI18N(settings *app_settings.Store, shipped fs.FS) *I18N {
+	return &I18N{settings: settings, shipped: shipped}
+}
 
-	entries, err := os.ReadDir(localesDir)
+// ClearCache забывает собранные переводы, чтобы следующий запрос перечитал файлы.
+// Вызывается при изменении файлов переводов.
+func (i *I18N) ClearCache() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cache = nil
+}
+
+// LocalesDir возвращает папку пользовательских переводов (расположение выбирает data_dir).
+// Папка создаётся, чтобы было видно, куда класть свои переводы.
+func LocalesDir() string {
+	dir, err := data_dir.Dir(data_dir.Locales)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("директория 'locales' не найдена: %w", err)
-		}
-		return nil, fmt.Errorf("не удалось прочитать директорию 'locales': %w", err)
+		fmt.Printf("Ошибка создания папки переводов: %v\n", err)
+		return data_dir.Path(data_dir.Locales)
 	}
+	return dir
+}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		filename := entry.Name()
-		if filepath.Ext(filename) != ".json" {
-			continue
-		}
-
-		code := strings.TrimSuffix(filename, filepath.Ext(filename))
-		path := filepath.Join(localesDir, filename)
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			fmt.Printf("Ошибка чтения файла '%s': %v\n", path, err)
-			continue
-		}
-
-		var jsonMap map[string]interface{}
-		if err := json.Unmarshal(data, &jsonMap); err != nil {
-			fmt.Printf("Ошибка десериализации JSON из файла '%s': %v\n", path, err)
-			continue
-		}
+// GetLanguages возвращает список доступных языков: встроенные и добавленные пользователем.
+// Для каждого языка возвращаются код (имя файла), имя (поле "lang_name" в JSON)
+// и источник: "builtin", "user" или "builtin+user".
+func (i *I18N) GetLanguages() ([]map[string]string, error) { // Изменено на метод
+	locales := i.catalog().locales()
+	if len(locales) == 0 {
+		return nil, fmt.Errorf("не найдено ни одного перевода")
+	}
 
+	codes := sortedCodes(locales)
+	langs := make([]map[string]string, 0, len(codes))
+	for _, code := range codes {
+		l := locales[code]
 		name := code // По умолчанию имя равно коду
-		if langName, ok := jsonMap["lang_name"].(string); ok {
+		if langName := l.entries["lang_name"]; langName != "" {
 			name = langName
 		}
-
 		langs = append(langs, map[string]string{
-			"code": code,
-			"name": name,
+			"code":   code,
+			"name":   name,
+			"source": l.source(),
 		})
 	}
-
 	return langs, nil
 }
 
 // GetCurrentLanguage возвращает текущий выбранный язык из настроек приложения.
 func (i *I18N) GetCurrentLanguage() (string, error) { // Изменено на метод
-	settings, err := app_settings.LoadSettings()
-	if err != nil {
-		return "", fmt.Errorf("не удалось загрузить настройки для получения текущего языка: %w", err)
-	}
-	return settings.Language, nil
+	return i.settings.Get().Language, nil
 }
 
 // SwitchLanguage изменяет текущий язык в настройках приложения и сохраняет их.
+// Остальные сервисы и фронтенд узнают о смене по обычному уведомлению об изменении настроек.
 func (i *I18N) SwitchLanguage(newLang string) error { // Изменено на метод
 	fmt.Printf("Переключение языка на: %s\n", newLang)
-	settings, err := app_settings.LoadSettings()
-	if err != nil {
-		return fmt.Errorf("не удалось загрузить настройки для переключения языка: %w", err)
-	}
-
-	settings.Language = newLang
-
-	if err := app_settings.SaveSettings(&settings); err != nil {
-		return fmt.Errorf("не удалось сохранить настройки после переключения языка: %w", err)
+	if _, err := i.settings.UpdateFields(map[string]any{"language": newLang}); err != nil {
+		return fmt.Errorf("не удалось переключить язык: %w", err)
 	}
 	return nil
 }
 
 // GetTranslationsCurrent возвращает переводы для текущего выбранного языка.
 func (i *I18N) GetTranslationsCurrent() (map[string]string, error) { // Изменено на метод
-	settings, err := app_settings.LoadSettings()
-	if err != nil {
-		return nil, fmt.Errorf("не удалось загрузить настройки для получения текущих переводов: %w", err)
-	}
-	langCode := settings.Language
-	return i.GetTranslations(langCode) // Вызов метода
+	return i.GetTranslations(i.settings.Get().Language) // Вызов метода
 }
 
-// GetTranslations возвращает переводы для указанного кода языка.
+// GetTranslations возвращает переводы для указанного кода языка по полным ключам
+// ("hotkeys.tooltips.click_for_bind") и, где это однозначно, по коротким ("click_for_bind").
+// Ключи из файла пользователя заменяют встроенные, поэтому в своём файле достаточно
+// перечислить только изменённые строки. Недостающие строки берутся из запасных языков
+// (см. GetTranslationsWithFallback).
 func (i *I18N) GetTranslations(langCode string) (map[string]string, error) { // Изменено на метод
-	path := fmt.Sprintf("locales/%s.json", langCode)
-	data, err := os.ReadFile(path)
+	t, err := i.GetTranslationsWithFallback(langCode)
+	return t.Entries, err
+}
+
+// Translate возвращает перевод key на текущем языке с подставленными параметрами params,
+// с теми же множественными числами и подстановками, что и $t на фронтенде (см. Format).
+// Если ключа нет, возвращается сам ключ.
+// Эта функция привязана к фронтенду Wails.
+func (i *I18N) Translate(key string, params map[string]any) string {
+	return i.translate(i.settings.Get().Language, key, params)
+}
+
+func (i *I18N) translate(lang, key string, params map[string]any) string {
+	t, err := i.GetTranslationsWithFallback(lang)
+	if err != nil && lang != DefaultLanguage {
+		// Языка нет совсем: строки и правила множественного числа берутся из английского
+		lang = DefaultLanguage
+		t, err = i.GetTranslationsWithFallback(lang)
+	}
+	message, ok := t.Entries[strings.ToLower(key)]
+	if err != nil || !ok {
+		return key
+	}
+	text, err := Format(lang, message, params)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// Если файл не найден, возвращаем пустую карту и ошибку
-			return make(map[string]string), fmt.Errorf("файл перевода для '%s' не найден: %w", langCode, err)
-		}
-		return nil, fmt.Errorf("не удалось прочитать файл перевода '%s': %w", path, err)
+		fmt.Printf("Ошибка в переводе '%s' (%s): %v\n", key, lang, err)
 	}
+	return text
+}
 
-	var jsonValue interface{}
-	if err := json.Unmarshal(data, &jsonValue); err != nil {
-		return nil, fmt.Errorf("не удалось десериализовать JSON перевода из файла '%s': %w", path, err)
+// catalog возвращает источники переводов: встроенные и папку пользователя поверх них.
+func (i *I18N) catalog() catalog {
+	var c catalog
+	if i.shipped != nil {
+		c = append(c, source{fsys: i.shipped, origin: "встроенный"})
 	}
-
-	return flattenJSON(jsonValue), nil
+	userDir := LocalesDir()
+	return append(c, source{fsys: os.DirFS(userDir), origin: userDir, user: true})
 }
 
-// flattenJSON рекурсивно преобразует вложенный JSON-объект в плоскую карту.
-// Эта функция остается обычной функцией, так как она является внутренней вспомогательной.
+// flattenJSON преобразует вложенный JSON-объект в плоскую карту. Ключи записываются
+// через точку в нижнем регистре: {"HOTKEYS": {"TOOLTIPS": {"click_for_bind": "..."}}}
+// даёт "hotkeys.tooltips.click_for_bind", поэтому одинаковые имена в разных разделах не смешиваются.
 func flattenJSON(value interface{}) map[string]string {
 	result := make(map[string]string)
-	if obj, ok := value.(map[string]interface{}); ok {
-		for k, v := range obj {
-			key := strings.ToLower(k) // Преобразуем ключ в нижний регистр
-			switch val := v.(type) {
-			case map[string]interface{}:
-				// Рекурсивно обрабатываем вложенные объекты
-				nested := flattenJSON(val)
-				// Добавляем вложенные ключи без префикса
-				for nk, nv := range nested {
-					result[nk] = nv
-				}
-			case string:
-				result[key] = val
-			default:
-				// Игнорируем другие типы значений
-			}
+	flattenInto(result, "", value)
+	return result
+}
+
+func flattenInto(result map[string]string, prefix string, value interface{}) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for k, v := range obj {
+		key := prefix + strings.ToLower(k) // Преобразуем ключ в нижний регистр
+		switch val := v.(type) {
+		case map[string]interface{}:
+			flattenInto(result, key+".", val)
+		case string:
+			result[key] = val
+		default:
+			// Игнорируем другие типы значений
 		}
 	}
-	return result
+}
+
+// shortKey возвращает последнюю часть ключа: "hotkeys.tooltips.click_for_bind" -> "click_for_bind".
+func shortKey(key string) string {
+	return key[strings.LastIndex(key, ".")+1:]
+}
+
+// shortKeys подбирает короткие ключи (без разделов), которыми фронтенд пользовался
+// до появления полных ключей. Короткий ключ получает значение полного, только если встречается
+// в одном разделе; для остальных возвращаются конфликтующие полные ключи, и обращаться к ним
+// нужно по полному ключу.
+func shortKeys(entries map[string]string) (aliases map[string]string, collisions map[string][]string) {
+	owners := make(map[string][]string)
+	for key := range entries {
+		owners[shortKey(key)] = append(owners[shortKey(key)], key)
+	}
+
+	aliases = make(map[string]string)
+	collisions = make(map[string][]string)
+	for short, keys := range owners {
+		switch {
+		case len(keys) > 1:
+			sort.Strings(keys)
+			collisions[short] = keys
+		case keys[0] != short:
+			aliases[short] = keys[0]
+		}
+	}
+	return aliases, collisions
+}
+
+// reportCollisions пишет в журнал короткие ключи, которые нельзя использовать без раздела.
+func reportCollisions(langCode string, collisions map[string][]string) {
+	shorts := make([]string, 0, len(collisions))
+	for short := range collisions {
+		shorts = append(shorts, short)
+	}
+	sort.Strings(shorts)
+	for _, short := range shorts {
+		fmt.Printf("Перевод '%s': ключ '%s' есть в нескольких разделах (%s), используйте полный ключ\n",
+			langCode, short, strings.Join(collisions[short], ", "))
+	}
 }
@@ -0,0 +1,244 @@
+package i18n
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"lce/backend/modules/data_dir"
+)
+
+// newTestI18N подставляет встроенные переводы en и ru и возвращает папку пользовательских переводов.
+func newTestI18N(t *testing.T) (*I18N, string) {
+	t.Helper()
+	dir := t.TempDir()
+	data_dir.Use(data_dir.Layout{Data: dir, Assets: dir})
+	shipped := fstest.MapFS{
+		"en.json": {Data: []byte(`{"lang_name": "English", "TITLE": {"search": "Search", "close_tooltip": "Close"}}`)},
+		"ru.json": {Data: []byte(`{"lang_name": "Русский", "TITLE": {"search": "Поиск"}}`)},
+	}
+	return NewI18N(nil, shipped), data_dir.Path(data_dir.Locales)
+}
+
+func writeLocale(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslationsOverlay(t *testing.T) {
+	i, userDir := newTestI18N(t)
+
+	got, err := i.GetTranslations("en")
+	if err != nil || got["search"] != "Search" {
+		t.Fatalf("GetTranslations(en) = %v, %v", got, err)
+	}
+
+	// Пользовательский файл меняет одну строку, остальные остаются встроенными.
+	// До сброса кэша (его делает вотчер переводов) возвращаются прежние строки
+	writeLocale(t, userDir, "en.json", `{"TITLE": {"search": "Find"}}`)
+	if got, _ := i.GetTranslations("en"); got["search"] != "Search" {
+		t.Errorf("GetTranslations(en) до ClearCache = %v, want из кэша", got)
+	}
+	i.ClearCache()
+	got, err = i.GetTranslations("en")
+	if err != nil || got["search"] != "Find" || got["title.search"] != "Find" || got["close_tooltip"] != "Close" || got["lang_name"] != "English" {
+		t.Errorf("GetTranslations(en) с файлом пользователя = %v, %v", got, err)
+	}
+
+	if _, err := i.GetTranslations("de"); err == nil {
+		t.Error("GetTranslations(de) без файла не вернул ошибку")
+	}
+	if _, err := i.GetTranslations("../en"); err == nil {
+		t.Error("GetTranslations(../en) не вернул ошибку")
+	}
+}
+
+func TestLanguagesMerged(t *testing.T) {
+	i, userDir := newTestI18N(t)
+	writeLocale(t, userDir, "de.json", `{"lang_name": "Deutsch", "TITLE": {"search": "Suche"}}`)
+	writeLocale(t, userDir, "ru.json", `{"TITLE": {"search": "Найти"}}`)
+	writeLocale(t, userDir, "broken.json", `{"lang_name": `)
+
+	langs, err := i.GetLanguages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]string{
+		{"code": "de", "name": "Deutsch", "source": sourceUser},
+		{"code": "en", "name": "English", "source": sourceBuiltin},
+		{"code": "ru", "name": "Русский", "source": sourceMerged},
+	}
+	if len(langs) != len(want) {
+		t.Fatalf("GetLanguages() = %v, want %v", langs, want)
+	}
+	for n := range want {
+		for key, value := range want[n] {
+			if langs[n][key] != value {
+				t.Errorf("GetLanguages()[%d] = %v, want %v", n, langs[n], want[n])
+				break
+			}
+		}
+	}
+
+	if got, err := i.GetTranslations("de"); err != nil || got["search"] != "Suche" {
+		t.Errorf("GetTranslations(de) = %v, %v", got, err)
+	}
+}
+
+func TestNamespacedKeys(t *testing.T) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(`{
+		"lang_name": "English",
+		"HOTKEYS": {"cast": "Cast", "TOOLTIPS": {"click_for_bind": "Click for bind", "hint": "Hotkey"}},
+		"VISUALS": {"TOOLTIPS": {"hint": "Visual"}}
+	}`), &value); err != nil {
+		t.Fatal(err)
+	}
+	entries := flattenJSON(value)
+	if entries["hotkeys.tooltips.click_for_bind"] != "Click for bind" || entries["visuals.tooltips.hint"] != "Visual" {
+		t.Fatalf("flattenJSON() = %v", entries)
+	}
+
+	aliases, collisions := shortKeys(entries)
+	if aliases["click_for_bind"] != "hotkeys.tooltips.click_for_bind" || aliases["cast"] != "hotkeys.cast" || len(aliases) != 2 {
+		t.Errorf("aliases = %v", aliases)
+	}
+	if got := collisions["hint"]; len(got) != 2 || got[0] != "hotkeys.tooltips.hint" || got[1] != "visuals.tooltips.hint" {
+		t.Errorf("collisions = %v", collisions)
+	}
+	if len(collisions) != 1 {
+		t.Errorf("лишние конфликты: %v", collisions)
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	tests := map[string][]string{
+		"es-MX":   {"es-MX", "es", "en"},
+		"pt_BR":   {"pt_BR", "pt", "en"},
+		"ru":      {"ru", "en"},
+		"en":      {"en"},
+		"en-GB":   {"en-GB", "en"},
+		"zh-Hant": {"zh-Hant", "zh", "en"},
+	}
+	for code, want := range tests {
+		got := fallbackChain(code)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("fallbackChain(%s) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestTranslationsFallback(t *testing.T) {
+	shipped := fstest.MapFS{
+		"en.json":    {Data: []byte(`{"TITLE": {"search": "Search", "close_tooltip": "Close", "settings_tooltip": "Settings"}}`)},
+		"es.json":    {Data: []byte(`{"TITLE": {"search": "Buscar", "close_tooltip": ""}}`)},
+		"es-MX.json": {Data: []byte(`{"TITLE": {"search": "Búsqueda"}}`)},
+	}
+	c := catalog{{fsys: shipped, origin: "test"}}
+
+	got, err := resolve(c, "es-MX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Entries["search"] != "Búsqueda" || got.Entries["close_tooltip"] != "Close" || got.Entries["title.settings_tooltip"] != "Settings" {
+		t.Errorf("Entries = %v", got.Entries)
+	}
+	want := map[string]string{
+		"title.close_tooltip": "en", "close_tooltip": "en",
+		"title.settings_tooltip": "en", "settings_tooltip": "en",
+	}
+	if len(got.Fallback) != len(want) {
+		t.Errorf("Fallback = %v, want %v", got.Fallback, want)
+	}
+	for key, lang := range want {
+		if got.Fallback[key] != lang {
+			t.Errorf("Fallback[%s] = %q, want %q", key, got.Fallback[key], lang)
+		}
+	}
+	if strings.Join(got.Chain, ",") != "es-MX,es,en" {
+		t.Errorf("Chain = %v", got.Chain)
+	}
+
+	// Регионального варианта нет, но есть общий язык
+	if got, err := resolve(c, "es-AR"); err != nil || got.Entries["search"] != "Buscar" || got.Fallback["title.search"] != "es" {
+		t.Errorf("resolve(es-AR) = %+v, %v", got, err)
+	}
+	if _, err := resolve(c, "de"); err == nil {
+		t.Error("resolve(de) без файла не вернул ошибку")
+	}
+}
+
+func TestCompletenessReport(t *testing.T) {
+	shipped := fstest.MapFS{
+		"en.json": {Data: []byte(`{"lang_name": "English", "TITLE": {"search": "Search", "close_tooltip": "Close", "ok": "OK", "help": "Help"}}`)},
+		"es.json": {Data: []byte(`{"lang_name": "Español", "TITLE": {"search": "Buscar", "ok": "OK", "help": " ", "old_key": "Viejo"}}`)},
+	}
+	reports, err := Reports(shipped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("Reports() = %v, want только es", reports)
+	}
+	r := reports[0]
+	check := func(name string, got []string, want ...string) {
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	check("Missing", r.Missing, "title.close_tooltip")
+	check("Extra", r.Extra, "title.old_key")
+	check("Empty", r.Empty, "title.help")
+	check("Untranslated", r.Untranslated, "title.ok")
+	if r.Total != 5 || r.Translated != 2 || r.Complete() {
+		t.Errorf("report = %+v", r)
+	}
+}
+
+// Раздел строки в переводе может отличаться от английского файла; короткие ключи
+// при этом должны находиться по файлу самого языка, а не теряться из-за слияния с английским.
+func TestShippedShortKeys(t *testing.T) {
+	c := catalog{{fsys: os.DirFS(filepath.Join("..", "..", "..", "locales")), origin: "locales"}}
+	for _, code := range []string{"es", "ru"} {
+		got, err := resolve(c, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range []string{"select_path", "paths_found", "cancel_scan", "scan_progress"} {
+			if got.Entries[key] == "" {
+				t.Errorf("resolve(%s).Entries[%s] пуст", code, key)
+			} else if lang, ok := got.Fallback[key]; ok {
+				t.Errorf("resolve(%s).Entries[%s] взят из %s", code, key, lang)
+			}
+		}
+		if len(got.collisions) != 0 {
+			t.Errorf("resolve(%s): неоднозначные короткие ключи %v", code, got.collisions)
+		}
+	}
+}
+
+// TestShippedLocales проверяет переводы из locales по правилам файла перевода
+// и выводит отчёт о полноте:
+//
+//	go test ./backend/modules/i18n -run TestShippedLocales -v
+func TestShippedLocales(t *testing.T) {
+	shipped := os.DirFS(filepath.Join("..", "..", "..", "locales"))
+	for _, p := range Validate(shipped) {
+		t.Error(p)
+	}
+	reports, err := Reports(shipped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range reports {
+		t.Log(r)
+	}
+}
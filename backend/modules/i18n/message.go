@@ -0,0 +1,325 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Строки переводов записываются в формате ICU MessageFormat - том же, что понимает svelte-i18n
+// на фронтенде, поэтому одна строка одинаково форматируется в Go и в интерфейсе:
+//
+//	"{count, plural, one {# путь} few {# пути} many {# путей} other {# пути}} в {dir}"
+//
+// Поддерживаются именованные подстановки {name}, {name, number}, {name, plural, ...}
+// (категории CLDR и точные значения вида =0, смещение offset:N, # - число) и {name, select, ...}.
+// Апостроф экранирует фигурные скобки и #: '{' - это «{», два апострофа подряд - один апостроф.
+
+// messagePart - кусок разобранной строки: текст, число из plural (#) или подстановка.
+type messagePart struct {
+	text    string
+	hash    bool                  // # внутри варианта plural
+	arg     string                // имя параметра
+	kind    string                // "", "number", "plural", "select"
+	offset  float64               // offset:N у plural
+	options map[string]messageSeq // варианты plural и select
+}
+
+type messageSeq []messagePart
+
+// messageParser разбирает строку формата ICU.
+type messageParser struct {
+	src    string
+	pos    int
+	plural int // глубина вложенности в варианты plural: там # означает число
+}
+
+// parseMessage разбирает строку перевода.
+func parseMessage(src string) (messageSeq, error) {
+	p := &messageParser{src: src}
+	seq, err := p.sequence()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("лишняя '}'")
+	}
+	return seq, nil
+}
+
+func (p *messageParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("позиция %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// sequence читает текст и подстановки до закрывающей скобки варианта или конца строки.
+func (p *messageParser) sequence() (messageSeq, error) {
+	var seq messageSeq
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			seq = append(seq, messagePart{text: text.String()})
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.quoted(&text)
+		case c == '{':
+			flush()
+			part, err := p.argument()
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, part)
+		case c == '}':
+			flush()
+			return seq, nil
+		case c == '#' && p.plural > 0:
+			flush()
+			seq = append(seq, messagePart{hash: true})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	flush()
+	return seq, nil
+}
+
+// quoted обрабатывает апостроф: два апострофа подряд дают один, '{...' - текст до следующего апострофа,
+// иначе апостроф остаётся обычным символом.
+func (p *messageParser) quoted(text *strings.Builder) {
+	p.pos++
+	if p.pos >= len(p.src) {
+		text.WriteByte('\'')
+		return
+	}
+	switch p.src[p.pos] {
+	case '\'':
+		text.WriteByte('\'')
+		p.pos++
+	case '{', '}', '#':
+		end := strings.IndexByte(p.src[p.pos:], '\'')
+		if end < 0 {
+			end = len(p.src) - p.pos
+		}
+		text.WriteString(p.src[p.pos : p.pos+end])
+		p.pos += end + 1
+	default:
+		text.WriteByte('\'')
+	}
+}
+
+// argument читает подстановку от '{' до парной '}'.
+func (p *messageParser) argument() (messagePart, error) {
+	start := p.pos
+	p.pos++ // '{'
+	part := messagePart{arg: p.word()}
+	if part.arg == "" {
+		return part, p.errorf("нет имени параметра")
+	}
+	p.spaces()
+	if p.next('}') {
+		return part, nil
+	}
+	if !p.next(',') {
+		return part, p.errorf("ожидалась ',' или '}' после '%s'", part.arg)
+	}
+	p.spaces()
+	part.kind = p.word()
+	p.spaces()
+	switch part.kind {
+	case "number":
+		if p.next(',') {
+			p.word() // стиль (integer, percent) не меняет вывод в Go
+			p.spaces()
+		}
+		if !p.next('}') {
+			return part, p.errorf("ожидалась '}' после {%s, number", part.arg)
+		}
+		return part, nil
+	case "plural", "select":
+		if !p.next(',') {
+			return part, p.errorf("ожидалась ',' после {%s, %s", part.arg, part.kind)
+		}
+		if err := p.options(&part); err != nil {
+			return part, err
+		}
+		if _, ok := part.options[pluralOther]; !ok {
+			p.pos = start
+			return part, p.errorf("у {%s, %s} нет варианта other", part.arg, part.kind)
+		}
+		return part, nil
+	default:
+		return part, p.errorf("неизвестный тип '%s' у {%s}", part.kind, part.arg)
+	}
+}
+
+// options читает варианты plural и select до закрывающей '}' подстановки.
+func (p *messageParser) options(part *messagePart) error {
+	part.options = make(map[string]messageSeq)
+	for {
+		p.spaces()
+		if p.next('}') {
+			return nil
+		}
+		key := p.word()
+		if key == "" {
+			return p.errorf("ожидался вариант у {%s, %s}", part.arg, part.kind)
+		}
+		if part.kind == "plural" && strings.HasPrefix(key, "offset:") {
+			offset, err := strconv.ParseFloat(strings.TrimPrefix(key, "offset:"), 64)
+			if err != nil {
+				return p.errorf("неверное смещение '%s'", key)
+			}
+			part.offset = offset
+			continue
+		}
+		p.spaces()
+		if !p.next('{') {
+			return p.errorf("ожидалась '{' после варианта '%s'", key)
+		}
+		if part.kind == "plural" {
+			p.plural++
+		}
+		seq, err := p.sequence()
+		if part.kind == "plural" {
+			p.plural--
+		}
+		if err != nil {
+			return err
+		}
+		if !p.next('}') {
+			return p.errorf("не закрыт вариант '%s' у {%s}", key, part.arg)
+		}
+		part.options[key] = seq
+	}
+}
+
+// word читает имя: буквы, цифры и символы _ - = : . (для "=0" и "offset:1").
+func (p *messageParser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' || c == '{' || c == '}' || c == '\'' || c == '#' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *messageParser) spaces() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\n\r", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *messageParser) next(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// render подставляет параметры в разобранную строку. Отсутствующий параметр
+// выводится как {name}, чтобы ошибка в переводе была видна, а не терялась.
+func (seq messageSeq) render(b *strings.Builder, plural pluralRule, params map[string]any, number *float64) {
+	for _, part := range seq {
+		switch {
+		case part.hash:
+			if number != nil {
+				b.WriteString(formatNumber(*number))
+			}
+		case part.arg == "":
+			b.WriteString(part.text)
+		default:
+			value, ok := params[part.arg]
+			if !ok {
+				b.WriteString("{" + part.arg + "}")
+				continue
+			}
+			switch part.kind {
+			case "plural":
+				n, ok := toNumber(value)
+				if !ok {
+					b.WriteString(fmt.Sprint(value))
+					continue
+				}
+				shown := n - part.offset
+				option, ok := part.options["="+formatNumber(n)]
+				if !ok {
+					option, ok = part.options[plural(shown)]
+				}
+				if !ok {
+					option = part.options[pluralOther]
+				}
+				option.render(b, plural, params, &shown)
+			case "select":
+				option, ok := part.options[fmt.Sprint(value)]
+				if !ok {
+					option = part.options[pluralOther]
+				}
+				option.render(b, plural, params, number)
+			case "number":
+				if n, ok := toNumber(value); ok {
+					b.WriteString(formatNumber(n))
+				} else {
+					b.WriteString(fmt.Sprint(value))
+				}
+			default:
+				b.WriteString(fmt.Sprint(value))
+			}
+		}
+	}
+}
+
+// toNumber приводит параметр к числу: с фронтенда числа приходят как float64, из Go - как int.
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// Format подставляет параметры в строку перевода message по правилам множественного числа языка lang.
+// При ошибке в строке возвращает её без изменений вместе с ошибкой.
+func Format(lang, message string, params map[string]any) (string, error) {
+	if !strings.ContainsAny(message, "{}'") {
+		return message, nil
+	}
+	seq, err := parseMessage(message)
+	if err != nil {
+		return message, err
+	}
+	var b strings.Builder
+	seq.render(&b, pluralFor(lang), params, nil)
+	return b.String(), nil
+}
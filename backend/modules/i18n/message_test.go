@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestFormat(t *testing.T) {
+	const paths = "{count, plural, =0 {Пути не найдены} one {Найден # путь} few {Найдено # пути} many {Найдено # путей} other {Найдено # пути}}"
+	tests := []struct {
+		lang    string
+		message string
+		params  map[string]any
+		want    string
+	}{
+		{"ru", paths, map[string]any{"count": 0}, "Пути не найдены"},
+		{"ru", paths, map[string]any{"count": 1}, "Найден 1 путь"},
+		{"ru", paths, map[string]any{"count": 3}, "Найдено 3 пути"},
+		{"ru", paths, map[string]any{"count": 11}, "Найдено 11 путей"},
+		{"ru", paths, map[string]any{"count": 21}, "Найден 21 путь"},
+		{"ru", paths, map[string]any{"count": 112}, "Найдено 112 путей"},
+		{"ru", paths, map[string]any{"count": 1.5}, "Найдено 1.5 пути"},
+		{"ru", paths, map[string]any{"count": float64(24)}, "Найдено 24 пути"},
+		{"en", "{count, plural, one {# path} other {# paths}} in {dir}", map[string]any{"count": 1, "dir": "C:/W3"}, "1 path in C:/W3"},
+		{"es-MX", "{count, plural, one {# ruta} other {# rutas}}", map[string]any{"count": 2}, "2 rutas"},
+		{"en", "{n, plural, offset:1 =0 {nobody} one {{name}} other {{name} and # others}}", map[string]any{"n": 3, "name": "Vordik"}, "Vordik and 2 others"},
+		{"en", "{mode, select, poll {Polling every {ms, number} ms} other {Watching}}", map[string]any{"mode": "poll", "ms": 2000}, "Polling every 2000 ms"},
+		{"en", "{mode, select, poll {Polling} other {Watching}}", map[string]any{"mode": "auto"}, "Watching"},
+		{"en", "Hello, {name}!", nil, "Hello, {name}!"},
+		{"en", "Use '{name}' literally, it''s fine, you're welcome", nil, "Use {name} literally, it's fine, you're welcome"},
+		{"en", "No placeholders #1", nil, "No placeholders #1"},
+	}
+	for _, tt := range tests {
+		got, err := Format(tt.lang, tt.message, tt.params)
+		if err != nil || got != tt.want {
+			t.Errorf("Format(%s, %q, %v) = %q, %v; want %q", tt.lang, tt.message, tt.params, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	for _, message := range []string{
+		"{count, plural, one {# path}}",
+		"{count, plural, one {# path} other {# paths}",
+		"{count, unknown}",
+		"{}",
+		"extra }",
+	} {
+		got, err := Format("en", message, map[string]any{"count": 1})
+		if err == nil {
+			t.Errorf("Format(%q) = %q, want ошибку", message, got)
+		}
+		if got != message {
+			t.Errorf("Format(%q) при ошибке = %q, want строку без изменений", message, got)
+		}
+	}
+}
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    float64
+		want string
+	}{
+		{"ru", 1, pluralOne}, {"ru", 2, pluralFew}, {"ru", 5, pluralMany}, {"ru", 12, pluralMany},
+		{"ru", 22, pluralFew}, {"ru", 101, pluralOne}, {"ru", 0, pluralMany}, {"ru", 2.5, pluralOther},
+		{"en", 1, pluralOne}, {"en", 0, pluralOther}, {"en", 1.5, pluralOther},
+		{"fr", 0, pluralOne}, {"fr", 1.5, pluralOne}, {"fr", 2, pluralOther},
+		{"pl", 1, pluralOne}, {"pl", 22, pluralFew}, {"pl", 21, pluralMany},
+		{"ja", 1, pluralOther}, {"xx", 1, pluralOne},
+	}
+	for _, tt := range tests {
+		if got := pluralFor(tt.lang)(tt.n); got != tt.want {
+			t.Errorf("%s(%v) = %s, want %s", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	i, _ := newTestI18N(t)
+	i.shipped = fstest.MapFS{
+		"en.json": {Data: []byte(`{"SETTING": {"paths_found": "{count, plural, one {Found # path} other {Found # paths}}"}}`)},
+		"ru.json": {Data: []byte(`{"SETTING": {"paths_found": "{count, plural, one {Найден # путь} few {Найдено # пути} other {Найдено # путей}}"}}`)},
+	}
+	if got := i.translate("ru", "setting.paths_found", map[string]any{"count": 5}); got != "Найдено 5 путей" {
+		t.Errorf("translate(ru) = %q", got)
+	}
+	if got := i.translate("ru", "paths_found", map[string]any{"count": 2}); got != "Найдено 2 пути" {
+		t.Errorf("translate(ru) по короткому ключу = %q", got)
+	}
+	if got := i.translate("de", "paths_found", map[string]any{"count": 1}); got != "Found 1 path" {
+		t.Errorf("translate(de) = %q, want английский", got)
+	}
+	if got := i.translate("ru", "no_such_key", nil); got != "no_such_key" {
+		t.Errorf("translate() без ключа = %q", got)
+	}
+}
@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"math"
+	"strings"
+)
+
+// Категории множественного числа CLDR.
+const (
+	pluralZero  = "zero"
+	pluralOne   = "one"
+	pluralTwo   = "two"
+	pluralFew   = "few"
+	pluralMany  = "many"
+	pluralOther = "other"
+)
+
+// pluralRule выбирает категорию множественного числа для числа n.
+type pluralRule func(n float64) string
+
+// pluralRules - правила CLDR по базовому коду языка. Для языков, которых здесь нет,
+// используется правило английского (one для 1, иначе other).
+var pluralRules = map[string]pluralRule{
+	"en": pluralOneOther,
+	"es": pluralOneOther,
+	"de": pluralOneOther,
+	"it": pluralOneOther,
+	"pt": pluralOneOther,
+	"fr": pluralFrench,
+	"ru": pluralEastSlavic,
+	"uk": pluralEastSlavic,
+	"be": pluralEastSlavic,
+	"pl": pluralPolish,
+	"ja": pluralNone,
+	"ko": pluralNone,
+	"zh": pluralNone,
+}
+
+// pluralFor возвращает правило для языка: "es-MX" использует правило "es".
+func pluralFor(lang string) pluralRule {
+	base := strings.ToLower(lang)
+	if cut := strings.IndexAny(base, "-_"); cut > 0 {
+		base = base[:cut]
+	}
+	if rule, ok := pluralRules[base]; ok {
+		return rule
+	}
+	return pluralOneOther
+}
+
+// integer возвращает целую часть n и признак того, что дробной части нет.
+func integer(n float64) (int64, bool) {
+	n = math.Abs(n)
+	i := math.Trunc(n)
+	return int64(i), i == n
+}
+
+func pluralOneOther(n float64) string {
+	if i, whole := integer(n); whole && i == 1 {
+		return pluralOne
+	}
+	return pluralOther
+}
+
+func pluralFrench(n float64) string {
+	if i, _ := integer(n); i == 0 || i == 1 {
+		return pluralOne
+	}
+	return pluralOther
+}
+
+// pluralEastSlavic - русский, украинский, белорусский: 1 файл, 2 файла, 5 файлов, 1,5 файла.
+func pluralEastSlavic(n float64) string {
+	i, whole := integer(n)
+	if !whole {
+		return pluralOther
+	}
+	mod10, mod100 := i%10, i%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return pluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return pluralFew
+	default:
+		return pluralMany
+	}
+}
+
+func pluralPolish(n float64) string {
+	i, whole := integer(n)
+	if !whole {
+		return pluralOther
+	}
+	mod10, mod100 := i%10, i%100
+	switch {
+	case i == 1:
+		return pluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return pluralFew
+	default:
+		return pluralMany
+	}
+}
+
+func pluralNone(float64) string {
+	return pluralOther
+}
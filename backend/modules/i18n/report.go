@@ -0,0 +1,133 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// LocaleReport - полнота перевода одного языка по сравнению с английским.
+// Сравниваются полные ключи только файла самого языка, без запасных.
+type LocaleReport struct {
+	Language     string   `json:"language"`
+	Total        int      `json:"total"`        // строк в английском переводе
+	Translated   int      `json:"translated"`   // переведено: есть, не пусто и отличается от английского
+	Missing      []string `json:"missing"`      // нет в переводе: показывается запасной язык
+	Extra        []string `json:"extra"`        // нет в английском: устаревшие ключи или опечатки
+	Empty        []string `json:"empty"`        // пустые строки
+	Untranslated []string `json:"untranslated"` // совпадают с английскими
+}
+
+// Complete сообщает, что в переводе нет недостающих, лишних и пустых строк.
+// Совпадение с английским ошибкой не считается: часть строк (названия, команды) не переводится.
+func (r LocaleReport) Complete() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Empty) == 0
+}
+
+// String возвращает отчёт в виде текста для командной строки.
+func (r LocaleReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: переведено %d из %d", r.Language, r.Translated, r.Total)
+	for _, group := range []struct {
+		title string
+		keys  []string
+	}{
+		{"нет перевода", r.Missing},
+		{"лишние ключи", r.Extra},
+		{"пустые строки", r.Empty},
+		{"совпадают с английским", r.Untranslated},
+	} {
+		if len(group.keys) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s (%d):", group.title, len(group.keys))
+		for _, key := range group.keys {
+			fmt.Fprintf(&b, "\n    %s", key)
+		}
+	}
+	return b.String()
+}
+
+// notTranslatable - ключи, которые не сравниваются с английским.
+var notTranslatable = map[string]bool{
+	"lang_name": true,
+}
+
+// compareLocale сравнивает перевод entries с английским reference.
+func compareLocale(code string, entries, reference map[string]string) LocaleReport {
+	r := LocaleReport{
+		Language:     code,
+		Total:        len(reference),
+		Missing:      []string{},
+		Extra:        []string{},
+		Empty:        []string{},
+		Untranslated: []string{},
+	}
+	for key, english := range reference {
+		value, ok := entries[key]
+		switch {
+		case !ok:
+			r.Missing = append(r.Missing, key)
+		case strings.TrimSpace(value) == "":
+			r.Empty = append(r.Empty, key)
+		case value == english && code != DefaultLanguage && !notTranslatable[key]:
+			r.Untranslated = append(r.Untranslated, key)
+		default:
+			r.Translated++
+		}
+	}
+	for key := range entries {
+		if _, ok := reference[key]; !ok {
+			r.Extra = append(r.Extra, key)
+		}
+	}
+	for _, keys := range [][]string{r.Missing, r.Extra, r.Empty, r.Untranslated} {
+		sort.Strings(keys)
+	}
+	return r
+}
+
+// reports строит отчёты для языков codes; без кодов - для всех языков каталога, кроме английского.
+func (c catalog) reports(codes []string) ([]LocaleReport, error) {
+	reference, ok, err := c.load(DefaultLanguage)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("нет английского перевода (%s.json), сравнивать не с чем", DefaultLanguage)
+	}
+	if len(codes) == 0 {
+		for _, code := range sortedCodes(c.locales()) {
+			if code != DefaultLanguage {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	reports := make([]LocaleReport, 0, len(codes))
+	for _, code := range codes {
+		entries, ok, err := c.load(code)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("файл перевода для '%s' не найден", code)
+		}
+		reports = append(reports, compareLocale(code, entries, reference))
+	}
+	return reports, nil
+}
+
+// GetCompletenessReports возвращает отчёты о полноте переводов (встроенных вместе с файлами
+// пользователя) для языков codes, а без кодов - для всех языков, кроме английского.
+// Эта функция привязана к фронтенду Wails.
+func (i *I18N) GetCompletenessReports(codes []string) ([]LocaleReport, error) {
+	return i.catalog().reports(codes)
+}
+
+// Reports строит отчёты о полноте переводов из одной папки (например, locales в исходниках)
+// без учёта файлов пользователя. Используется командой locale-report и тестами.
+func Reports(fsys fs.FS, codes ...string) ([]LocaleReport, error) {
+	return catalog{{fsys: fsys, origin: "locales"}}.reports(codes)
+}
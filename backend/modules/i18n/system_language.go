@@ -0,0 +1,82 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// normalizeLocale приводит системное обозначение локали к коду языка:
+// "ru_RU.UTF-8@euro" -> "ru-RU". Для "C" и "POSIX" (язык не задан) возвращает пустую строку.
+func normalizeLocale(locale string) string {
+	if cut := strings.IndexAny(locale, ".@"); cut >= 0 {
+		locale = locale[:cut]
+	}
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	return locale
+}
+
+// localeFromEnv возвращает локаль из переменных окружения в порядке POSIX: LC_ALL, LC_MESSAGES, LANG.
+func localeFromEnv(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := normalizeLocale(getenv(name)); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
+
+// matchLanguage подбирает для системной локали доступный перевод по цепочке запасных языков:
+// "es-MX" -> es-MX, es, затем любой вариант того же языка (es-AR), иначе английский.
+// Коды сравниваются без учёта регистра и разделителя ("pt_br" и "pt-BR" совпадают).
+func matchLanguage(system string, available []string) string {
+	key := func(code string) string {
+		return strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+	}
+	index := make(map[string]string, len(available))
+	for _, code := range available {
+		index[key(code)] = code
+	}
+
+	system = normalizeLocale(system)
+	if system == "" {
+		return DefaultLanguage
+	}
+	parents := languageParents(system)
+	for _, lang := range parents {
+		if code, ok := index[key(lang)]; ok {
+			return code
+		}
+	}
+	base := key(parents[len(parents)-1])
+	for _, code := range sortedStrings(available) {
+		if strings.HasPrefix(key(code), base+"-") {
+			return code
+		}
+	}
+	return DefaultLanguage
+}
+
+// DetectSystemLanguage возвращает код доступного перевода, лучше всего подходящего
+// к языку операционной системы (LANG/LC_* в Linux, языковые параметры пользователя в Windows).
+// Эта функция привязана к фронтенду Wails.
+func (i *I18N) DetectSystemLanguage() string {
+	return matchLanguage(systemLocale(), sortedCodes(i.catalog().locales()))
+}
+
+// UseSystemLanguage выбирает язык по системе и сохраняет его в настройках.
+// Вызывается при первом запуске, пока пользователь не выбрал язык сам.
+// Эта функция привязана к фронтенду Wails.
+func (i *I18N) UseSystemLanguage() (string, error) {
+	lang := i.DetectSystemLanguage()
+	fmt.Printf("Язык системы: %q, выбран перевод: %s\n", systemLocale(), lang)
+	if lang == i.settings.Get().Language {
+		return lang, nil
+	}
+	if err := i.SwitchLanguage(lang); err != nil {
+		return "", err
+	}
+	return lang, nil
+}
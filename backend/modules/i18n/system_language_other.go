@@ -0,0 +1,10 @@
+//go:build !windows
+
+package i18n
+
+import "os"
+
+// systemLocale возвращает локаль из переменных окружения LC_ALL, LC_MESSAGES и LANG.
+func systemLocale() string {
+	return localeFromEnv(os.Getenv)
+}
@@ -0,0 +1,44 @@
+package i18n
+
+import "testing"
+
+func TestLocaleFromEnv(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"LANG": "ru_RU.UTF-8"}, "ru-RU"},
+		{map[string]string{"LANG": "en_US.UTF-8", "LC_MESSAGES": "es_ES@euro"}, "es-ES"},
+		{map[string]string{"LANG": "ru_RU.UTF-8", "LC_ALL": "C"}, "ru-RU"},
+		{map[string]string{"LC_ALL": "pt_BR.utf8", "LANG": "ru_RU.UTF-8"}, "pt-BR"},
+		{map[string]string{"LANG": "POSIX"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := localeFromEnv(func(name string) string { return tt.env[name] }); got != tt.want {
+			t.Errorf("localeFromEnv(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestMatchLanguage(t *testing.T) {
+	available := []string{"en", "es", "pt-BR", "ru"}
+	tests := map[string]string{
+		"ru-RU":       "ru",
+		"ru_UA.UTF-8": "ru",
+		"es-MX":       "es",
+		"es":          "es",
+		"pt":          "pt-BR",
+		"pt_PT":       "pt-BR",
+		"PT-br":       "pt-BR",
+		"de-DE":       "en",
+		"en-GB":       "en",
+		"":            "en",
+		"C":           "en",
+	}
+	for system, want := range tests {
+		if got := matchLanguage(system, available); got != want {
+			t.Errorf("matchLanguage(%q) = %q, want %q", system, got, want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package i18n
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetUserDefaultLocaleName = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetUserDefaultLocaleName")
+
+// systemLocale возвращает локаль пользователя Windows ("ru-RU"), а если её не удалось
+// получить - первый из предпочитаемых языков интерфейса.
+func systemLocale() string {
+	// LOCALE_NAME_MAX_LENGTH = 85
+	buf := make([]uint16, 85)
+	if n, _, _ := procGetUserDefaultLocaleName.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf))); n > 0 {
+		return normalizeLocale(windows.UTF16ToString(buf))
+	}
+	if langs, err := windows.GetUserPreferredUILanguages(windows.MUI_LANGUAGE_NAME); err == nil && len(langs) > 0 {
+		return normalizeLocale(langs[0])
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Режим переводчика: переводы читаются не из встроенных в программу файлов, а из папки
+// locales на диске, и каждое сохранение файла сразу проверяется и показывается в открытых окнах.
+// Включается флагом --translator (папка locales рядом с программой или в рабочей папке),
+// флагом --translator=<папка> или переменной окружения LCE_TRANSLATOR (1 или путь к папке).
+const (
+	TranslatorFlag = "--translator"
+	TranslatorEnv  = "LCE_TRANSLATOR"
+)
+
+// EventLocaleReloaded отправляется в режиме переводчика после изменения файла перевода
+// (данные: LocaleReload).
+const EventLocaleReloaded = "locale-reloaded"
+
+// EventEmitter отправляет события фронтенду (реализуется app.Event в Wails).
+type EventEmitter interface {
+	Emit(name string, data ...any)
+}
+
+// LocaleReload - результат перезагрузки файла перевода.
+type LocaleReload struct {
+	File     string          `json:"file"`
+	Language string          `json:"language"` // текущий язык интерфейса
+	Problems []LocaleProblem `json:"problems"` // нарушения правил в изменённом файле
+	// Translations - новые переводы текущего языка; nil, если файл не касается текущего языка
+	// или не читается (тогда в окнах остаются прежние строки).
+	Translations *Translations `json:"translations"`
+}
+
+// TranslatorDir возвращает папку с переводами для режима переводчика и признак того, что режим включён.
+func TranslatorDir(args []string) (string, bool) {
+	value, enabled := os.LookupEnv(TranslatorEnv)
+	if enabled && (value == "" || value == "0") {
+		enabled = false
+	}
+	if value == "1" {
+		value = ""
+	}
+	for _, arg := range args {
+		if arg == TranslatorFlag {
+			enabled, value = true, ""
+		} else if dir, ok := strings.CutPrefix(arg, TranslatorFlag+"="); ok {
+			enabled, value = true, dir
+		}
+	}
+	if !enabled {
+		return "", false
+	}
+	if value != "" {
+		dir, err := filepath.Abs(value)
+		if err != nil {
+			return value, true
+		}
+		return dir, true
+	}
+
+	// Рядом с программой (установленное приложение), иначе в рабочей папке (исходники)
+	var candidates []string
+	if exe, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(exe), "locales"))
+	}
+	if wd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, filepath.Join(wd, "locales"))
+	}
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, true
+		}
+	}
+	if len(candidates) == 0 {
+		return "locales", true
+	}
+	return candidates[len(candidates)-1], true
+}
+
+// Publish задаёт, куда отправлять события о перезагрузке переводов.
+func (i *I18N) Publish(events EventEmitter) {
+	i.events = events
+}
+
+// ReloadLocale проверяет изменённый файл перевода path и отправляет фронтенду EventLocaleReloaded
+// с новыми переводами текущего языка, если файл входит в его цепочку запасных языков.
+// Вызывается вотчером в режиме переводчика.
+func (i *I18N) ReloadLocale(path string, removed bool) {
+	i.ClearCache()
+	code := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	reload := LocaleReload{
+		File:     path,
+		Language: i.settings.Get().Language,
+		Problems: []LocaleProblem{},
+	}
+	if !removed {
+		if data, err := os.ReadFile(path); err == nil {
+			// Файл пользователя поверх встроенного может не повторять lang_name
+			partial := filepath.Clean(filepath.Dir(path)) == filepath.Clean(LocalesDir()) && i.hasShipped(code)
+			reload.Problems = append(reload.Problems, validateLocale(path, data, partial)...)
+		} else {
+			reload.Problems = append(reload.Problems, LocaleProblem{File: path, Message: err.Error()})
+		}
+	}
+	for _, p := range reload.Problems {
+		fmt.Printf("Ошибка в переводе: %s\n", p)
+	}
+
+	if slices.Contains(fallbackChain(reload.Language), code) {
+		t, err := i.GetTranslationsWithFallback(reload.Language)
+		if err != nil {
+			fmt.Printf("Перевод %s не перезагружен: %v\n", reload.Language, err)
+		} else {
+			reload.Translations = &t
+			fmt.Printf("Перевод %s перезагружен после изменения %s\n", reload.Language, path)
+		}
+	}
+	if i.events != nil {
+		i.events.Emit(EventLocaleReloaded, reload)
+	}
+}
+
+// hasShipped сообщает, есть ли встроенный перевод языка code.
+func (i *I18N) hasShipped(code string) bool {
+	if i.shipped == nil {
+		return false
+	}
+	_, err := fs.Stat(i.shipped, code+".json")
+	return err == nil
+}
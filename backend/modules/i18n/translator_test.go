@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lce/backend/modules/app_settings"
+	"lce/backend/modules/data_dir"
+)
+
+type recordingEmitter struct {
+	reloads []LocaleReload
+}
+
+func (r *recordingEmitter) Emit(name string, data ...any) {
+	if name == EventLocaleReloaded && len(data) == 1 {
+		r.reloads = append(r.reloads, data[0].(LocaleReload))
+	}
+}
+
+func TestTranslatorDir(t *testing.T) {
+	t.Setenv(TranslatorEnv, "")
+	if _, ok := TranslatorDir(nil); ok {
+		t.Error("режим переводчика включён без флага")
+	}
+	if _, ok := TranslatorDir([]string{"--portable"}); ok {
+		t.Error("режим переводчика включён чужим флагом")
+	}
+
+	dir := t.TempDir()
+	if got, ok := TranslatorDir([]string{TranslatorFlag + "=" + dir}); !ok || got != dir {
+		t.Errorf("TranslatorDir(--translator=dir) = %q, %v", got, ok)
+	}
+	t.Setenv(TranslatorEnv, dir)
+	if got, ok := TranslatorDir(nil); !ok || got != dir {
+		t.Errorf("TranslatorDir() с %s = %q, %v", TranslatorEnv, got, ok)
+	}
+	t.Setenv(TranslatorEnv, "0")
+	if _, ok := TranslatorDir(nil); ok {
+		t.Errorf("режим переводчика включён при %s=0", TranslatorEnv)
+	}
+	if got, ok := TranslatorDir([]string{TranslatorFlag}); !ok || filepath.Base(got) != "locales" {
+		t.Errorf("TranslatorDir(--translator) = %q, %v", got, ok)
+	}
+}
+
+func TestReloadLocale(t *testing.T) {
+	dataDir := t.TempDir()
+	data_dir.Use(data_dir.Layout{Data: dataDir, Assets: dataDir})
+	store := app_settings.NewStore()
+	if _, err := store.UpdateFields(map[string]any{"language": "ru"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sources := t.TempDir()
+	writeLocale(t, sources, "en.json", `{"lang_name": "English", "TITLE": {"search": "Search", "close_tooltip": "Close"}}`)
+	writeLocale(t, sources, "ru.json", `{"lang_name": "Русский", "TITLE": {"search": "Поиск"}}`)
+	writeLocale(t, sources, "es.json", `{"lang_name": "Español", "TITLE": {"search": "Buscar"}}`)
+
+	events := &recordingEmitter{}
+	i := NewI18N(store, os.DirFS(sources))
+	i.Publish(events)
+
+	// Переводчик сохраняет ru.json с новой строкой
+	writeLocale(t, sources, "ru.json", `{"lang_name": "Русский", "TITLE": {"search": "Найти", "close_tooltip": "Закрыть"}}`)
+	i.ReloadLocale(filepath.Join(sources, "ru.json"), false)
+	// en.json входит в цепочку ru, es.json - нет
+	writeLocale(t, sources, "en.json", `{"TITLE": {"search": "Search", "close_tooltip": "Close", "new_key": "<img src=x>"}}`)
+	i.ReloadLocale(filepath.Join(sources, "en.json"), false)
+	i.ReloadLocale(filepath.Join(sources, "es.json"), false)
+	// Файл сохранён с ошибкой: строки остаются прежними
+	writeLocale(t, sources, "ru.json", `{"lang_name": "Русский", `)
+	i.ReloadLocale(filepath.Join(sources, "ru.json"), false)
+
+	if len(events.reloads) != 4 {
+		t.Fatalf("событий %d, want 4", len(events.reloads))
+	}
+	first := events.reloads[0]
+	if first.Translations == nil || first.Translations.Entries["search"] != "Найти" || first.Translations.Entries["close_tooltip"] != "Закрыть" || len(first.Problems) != 0 {
+		t.Errorf("после правки ru.json: %+v", first)
+	}
+	second := events.reloads[1]
+	if second.Translations == nil || second.Translations.Fallback["title.new_key"] != "en" || len(second.Problems) != 2 {
+		t.Errorf("после правки en.json (нет lang_name, разметка вне подсказки): %+v", second)
+	}
+	if third := events.reloads[2]; third.Translations != nil {
+		t.Errorf("правка es.json прислала переводы ru: %+v", third)
+	}
+	if broken := events.reloads[3]; broken.Translations != nil || len(broken.Problems) != 1 {
+		t.Errorf("после повреждения ru.json: %+v", broken)
+	}
+}
@@ -0,0 +1,245 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Правила файла перевода:
+//   - корень - объект с непустой строкой lang_name (кроме файла пользователя, дополняющего встроенный);
+//   - значения - только строки и вложенные объекты-разделы;
+//   - в именах ключей нет точек: точка разделяет разделы в полном ключе;
+//   - строки записаны в формате ICU без ошибок (см. Format);
+//   - HTML-разметка допустима только в подсказках и только из тегов allowedTags без атрибутов.
+//
+// Подсказки показываются через tippy с allowHTML, поэтому при загрузке их разметка
+// дополнительно очищается (sanitizeMarkup): файл пользователя не может вставить в интерфейс свой HTML.
+
+// allowedTags - теги, разрешённые в подсказках.
+var allowedTags = map[string]bool{
+	"br":     true,
+	"b":      true,
+	"i":      true,
+	"strong": true,
+	"em":     true,
+}
+
+var (
+	tagPattern    = regexp.MustCompile(`^<\s*(/?)\s*([a-zA-Z][a-zA-Z0-9]*)([^<>]*)>`)
+	entityPattern = regexp.MustCompile(`^&(#[0-9]+|#x[0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+)
+
+// LocaleProblem - нарушение правил в файле перевода.
+type LocaleProblem struct {
+	File    string `json:"file"`
+	Key     string `json:"key"` // полный ключ; пусто - файл целиком
+	Message string `json:"message"`
+}
+
+func (p LocaleProblem) String() string {
+	if p.Key == "" {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.File, p.Key, p.Message)
+}
+
+// isMarkupKey сообщает, что строка показывается как подсказка и может содержать разметку.
+func isMarkupKey(key string) bool {
+	return strings.Contains(key, "tooltip")
+}
+
+// validateLocale проверяет содержимое файла перевода file.
+// partial - файл пользователя поверх встроенного: lang_name в нём необязателен.
+func validateLocale(file string, data []byte, partial bool) []LocaleProblem {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return []LocaleProblem{{File: file, Message: fmt.Sprintf("неверный JSON: %v", err)}}
+	}
+	obj, ok := root.(map[string]any)
+	if !ok {
+		return []LocaleProblem{{File: file, Message: "корень файла должен быть объектом"}}
+	}
+
+	var problems []LocaleProblem
+	add := func(key, format string, args ...any) {
+		problems = append(problems, LocaleProblem{File: file, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	if name, ok := obj["lang_name"].(string); !partial && (!ok || strings.TrimSpace(name) == "") {
+		add("lang_name", "нет названия языка")
+	}
+
+	var walk func(prefix string, obj map[string]any)
+	walk = func(prefix string, obj map[string]any) {
+		seen := make(map[string]string)
+		for k, v := range obj {
+			key := prefix + strings.ToLower(k)
+			if strings.Contains(k, ".") {
+				add(key, "точка в имени ключа: она разделяет разделы")
+			}
+			if other, dup := seen[strings.ToLower(k)]; dup {
+				add(key, "ключи '%s' и '%s' различаются только регистром", other, k)
+			}
+			seen[strings.ToLower(k)] = k
+
+			switch val := v.(type) {
+			case map[string]any:
+				walk(key+".", val)
+			case string:
+				if _, err := parseMessage(val); err != nil {
+					add(key, "ошибка в формате строки: %v", err)
+				}
+				for _, tag := range findTags(val) {
+					switch {
+					case !isMarkupKey(key):
+						add(key, "разметка %s допустима только в подсказках", tag)
+					case !allowedTag(tag):
+						add(key, "недопустимая разметка %s", tag)
+					}
+				}
+			default:
+				add(key, "значение должно быть строкой или разделом, а не %s", jsonKind(v))
+			}
+		}
+	}
+	walk("", obj)
+
+	sort.Slice(problems, func(a, b int) bool { return problems[a].Key < problems[b].Key })
+	return problems
+}
+
+// findTags возвращает все HTML-теги строки.
+func findTags(s string) []string {
+	var tags []string
+	for i := 0; i < len(s); i++ {
+		if s[i] != '<' {
+			continue
+		}
+		if m := tagPattern.FindString(s[i:]); m != "" {
+			tags = append(tags, m)
+		}
+	}
+	return tags
+}
+
+// allowedTag сообщает, что тег из списка разрешённых и без атрибутов.
+func allowedTag(tag string) bool {
+	m := tagPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return false
+	}
+	attrs := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m[3]), "/"))
+	return allowedTags[strings.ToLower(m[2])] && attrs == ""
+}
+
+// sanitizeMarkup оставляет в подсказке только разрешённые теги (в виде <br>, <b>, </b>),
+// а остальную разметку экранирует, чтобы она показывалась текстом.
+func sanitizeMarkup(s string) string {
+	if !strings.ContainsAny(s, "<>&") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '<':
+			if m := tagPattern.FindStringSubmatch(s[i:]); m != nil && allowedTag(m[0]) {
+				b.WriteString("<" + m[1] + strings.ToLower(m[2]) + ">")
+				i += len(m[0])
+				continue
+			}
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '&':
+			if m := entityPattern.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+			b.WriteString("&amp;")
+		default:
+			b.WriteByte(s[i])
+		}
+		i++
+	}
+	return b.String()
+}
+
+// sanitizeEntries очищает разметку подсказок в развёрнутом переводе.
+func sanitizeEntries(entries map[string]string) {
+	for key, value := range entries {
+		if isMarkupKey(key) {
+			entries[key] = sanitizeMarkup(value)
+		}
+	}
+}
+
+func jsonKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "логическое значение"
+	case float64:
+		return "число"
+	case []any:
+		return "массив"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// validate проверяет все файлы каталога.
+func (c catalog) validate() []LocaleProblem {
+	var problems []LocaleProblem
+	builtin := make(map[string]bool)
+	for _, src := range c {
+		entries, err := fs.ReadDir(src.fsys, ".")
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				problems = append(problems, LocaleProblem{File: src.origin, Message: err.Error()})
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			file := entry.Name()
+			if src.user {
+				file = filepath.Join(src.origin, file)
+			}
+			data, err := fs.ReadFile(src.fsys, entry.Name())
+			if err != nil {
+				problems = append(problems, LocaleProblem{File: file, Message: err.Error()})
+				continue
+			}
+			problems = append(problems, validateLocale(file, data, src.user && builtin[entry.Name()])...)
+			if !src.user {
+				builtin[entry.Name()] = true
+			}
+		}
+	}
+	return problems
+}
+
+// ValidateLocales проверяет встроенные переводы и файлы пользователя по правилам файла перевода.
+// Эта функция привязана к фронтенду Wails.
+func (i *I18N) ValidateLocales() []LocaleProblem {
+	problems := i.catalog().validate()
+	if problems == nil {
+		return []LocaleProblem{}
+	}
+	return problems
+}
+
+// Validate проверяет файлы переводов из одной папки (например, locales в исходниках).
+// Используется командой locale-report и тестами.
+func Validate(fsys fs.FS) []LocaleProblem {
+	return catalog{{fsys: fsys, origin: "locales"}}.validate()
+}
@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestValidateLocale(t *testing.T) {
+	data := `{
+		"TITLE": {"search": "Search", "count": 5, "list": ["a"], "bad.key": "x"},
+		"HOTKEYS": {
+			"label": "Line<br>break",
+			"TOOLTIPS": {"ok_tooltip": "One<br/>two <b>bold</b>", "img_tooltip": "<img src=x onerror=alert(1)>", "attr_tooltip": "<b class=x>b</b>"}
+		},
+		"broken": "{count, plural, one {#}}"
+	}`
+	problems := validateLocale("xx.json", []byte(data), false)
+	got := make(map[string]string)
+	for _, p := range problems {
+		got[p.Key] = p.Message
+	}
+	for _, key := range []string{
+		"lang_name",
+		"title.count",
+		"title.list",
+		"title.bad.key",
+		"hotkeys.label",
+		"hotkeys.tooltips.img_tooltip",
+		"hotkeys.tooltips.attr_tooltip",
+		"broken",
+	} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("нет нарушения для %s: %v", key, problems)
+		}
+	}
+	if msg, ok := got["hotkeys.tooltips.ok_tooltip"]; ok {
+		t.Errorf("лишнее нарушение для ok_tooltip: %s", msg)
+	}
+	if len(problems) != 8 {
+		t.Errorf("нарушений %d, want 8: %v", len(problems), problems)
+	}
+
+	// Файл пользователя поверх встроенного может не указывать lang_name
+	if problems := validateLocale("ru.json", []byte(`{"TITLE": {"search": "Найти"}}`), true); len(problems) != 0 {
+		t.Errorf("частичный файл: %v", problems)
+	}
+	if problems := validateLocale("ru.json", []byte(`["a"]`), true); len(problems) != 1 {
+		t.Errorf("корень-массив: %v", problems)
+	}
+}
+
+func TestSanitizeMarkup(t *testing.T) {
+	tests := map[string]string{
+		"Changes hotkeys to: <br> QWER <BR/> ASDF": "Changes hotkeys to: <br> QWER <br> ASDF",
+		"<b>bold</ b> and <i>italic</i>":           "<b>bold</b> and <i>italic</i>",
+		`<img src=x onerror="alert(1)">`:           `&lt;img src=x onerror="alert(1)"&gt;`,
+		"<b onclick=x>b</b>":                       "&lt;b onclick=x&gt;b</b>",
+		"1 < 2 & 3 > 2 &amp; &#169;":               "1 &lt; 2 &amp; 3 &gt; 2 &amp; &#169;",
+		"no markup":                                "no markup",
+	}
+	for in, want := range tests {
+		if got := sanitizeMarkup(in); got != want {
+			t.Errorf("sanitizeMarkup(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTooltipsSanitizedOnLoad(t *testing.T) {
+	c := catalog{{fsys: fstest.MapFS{
+		"en.json": {Data: []byte(`{"lang_name": "English", "label": "a < b", "TOOLTIPS": {"x_tooltip": "<script>alert(1)</script><br>"}}`)},
+	}, origin: "test"}}
+	entries, _, err := c.load("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := entries["tooltips.x_tooltip"]; strings.Contains(got, "<script") || !strings.HasSuffix(got, "<br>") {
+		t.Errorf("подсказка = %q", got)
+	}
+	// Обычные строки выводятся текстом, их экранирует Svelte
+	if entries["label"] != "a < b" {
+		t.Errorf("label = %q", entries["label"])
+	}
+}
@@ -0,0 +1,152 @@
+package paths_scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// cachedDir - сохранённое содержимое директории на момент последнего обхода.
+// Пока mtime директории не изменился, её не нужно перечитывать.
+type cachedDir struct {
+	ModTime int64    `json:"mtime"`   // время изменения директории (UnixNano)
+	Subdirs []string `json:"subdirs"` // имена всех поддиректорий
+	Target  bool     `json:"target"`  // в директории есть целевой файл
+}
+
+// scanCacheFile - формат файла кэша на диске.
+type scanCacheFile struct {
+	Targets string               `json:"targets"` // отпечаток шаблонов целевых файлов
+	Dirs    map[string]cachedDir `json:"dirs"`    // ключ - абсолютный путь директории
+}
+
+// ScanCache хранит результаты предыдущих обходов, чтобы повторное сканирование
+// перечитывало только изменившиеся директории.
+type ScanCache struct {
+	path string // файл кэша; пусто - кэш только в памяти
+
+	mu      sync.Mutex
+	targets string
+	dirs    map[string]cachedDir
+	visited map[string]cachedDir // директории, посещённые в текущем сканировании
+}
+
+// NewScanCache создаёт пустой кэш в памяти.
+func NewScanCache() *ScanCache {
+	return &ScanCache{dirs: make(map[string]cachedDir)}
+}
+
+// LoadScanCache загружает кэш из файла. Если файла нет или он повреждён, возвращается пустой кэш.
+func LoadScanCache(path string) *ScanCache {
+	c := NewScanCache()
+	c.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Не удалось прочитать кэш сканирования %s: %v", path, err)
+		}
+		return c
+	}
+
+	var file scanCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		log.Printf("Кэш сканирования %s повреждён и будет пересоздан: %v", path, err)
+		return c
+	}
+	c.targets = file.Targets
+	if file.Dirs != nil {
+		c.dirs = file.Dirs
+	}
+	return c
+}
+
+// begin готовит кэш к новому сканированию. Если изменились шаблоны целевых файлов,
+// сохранённые результаты сбрасываются.
+func (c *ScanCache) begin(rules ScanRules) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key := rules.targetsKey(); key != c.targets {
+		c.targets = key
+		c.dirs = make(map[string]cachedDir)
+	}
+	c.visited = make(map[string]cachedDir)
+}
+
+// lookup возвращает сохранённое содержимое директории, если её mtime не изменился.
+func (c *ScanCache) lookup(dir string, modTime int64) (cachedDir, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.dirs[dir]
+	if !ok || entry.ModTime != modTime || c.visited == nil {
+		return cachedDir{}, false
+	}
+	c.visited[dir] = entry
+	return entry, true
+}
+
+// store запоминает содержимое директории.
+func (c *ScanCache) store(dir string, entry cachedDir) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.visited == nil {
+		return // чтение зависшего диска вернулось уже после конца сканирования
+	}
+	c.visited[dir] = entry
+}
+
+// finish завершает сканирование. Для полностью обойдённых корней complete из кэша
+// удаляются непосещённые директории (удалённые папки забываются); для прерванных
+// отменой или таймаутом новые данные только дополняют старые. Данные других корней не трогаются.
+func (c *ScanCache) finish(complete []string) error {
+	c.mu.Lock()
+	for dir := range c.dirs {
+		if _, ok := c.visited[dir]; !ok && underAnyRoot(dir, complete) {
+			delete(c.dirs, dir)
+		}
+	}
+	for dir, entry := range c.visited {
+		c.dirs[dir] = entry
+	}
+	c.visited = nil
+	file := scanCacheFile{Targets: c.targets, Dirs: c.dirs}
+	data, err := json.Marshal(file)
+	c.mu.Unlock()
+
+	if c.path == "" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать кэш сканирования: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("не удалось создать директорию для кэша сканирования: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать кэш сканирования: %w", err)
+	}
+	return nil
+}
+
+// underAnyRoot проверяет, лежит ли dir внутри одного из корней.
+func underAnyRoot(dir string, roots []string) bool {
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		rel, err := filepath.Rel(root, dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len возвращает количество директорий в кэше.
+func (c *ScanCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.dirs)
+}
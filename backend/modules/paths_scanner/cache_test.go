@@ -0,0 +1,220 @@
+package paths_scanner
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// countingFS считает вызовы ReadDir для каждой директории.
+type countingFS struct {
+	fstest.MapFS
+	mu    *sync.Mutex
+	reads map[string]int
+}
+
+func (c countingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.mu.Lock()
+	c.reads[name]++
+	c.mu.Unlock()
+	return c.MapFS.ReadDir(name)
+}
+
+// countingFileSystem - один диск "/c" поверх countingFS.
+type countingFileSystem struct {
+	countingFS
+}
+
+func (c countingFileSystem) Roots() []string { return []string{"/c"} }
+
+func (c countingFileSystem) Open(root string) (fs.FS, error) {
+	if filepath.ToSlash(root) != "/c" {
+		return nil, fs.ErrNotExist
+	}
+	return c.countingFS, nil
+}
+
+func (c countingFileSystem) Stat(string) (fs.FileInfo, error) { return nil, fs.ErrNotExist }
+
+func (c countingFileSystem) Writable(string) bool { return true }
+
+func (c countingFileSystem) takeReads() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var dirs []string
+	for dir := range c.reads {
+		dirs = append(dirs, dir)
+		delete(c.reads, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+func dir(mtime time.Time) *fstest.MapFile {
+	return &fstest.MapFile{Mode: fs.ModeDir | 0755, ModTime: mtime}
+}
+
+func TestScanCacheSkipsUnchangedDirs(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	tree := fstest.MapFS{
+		".":                           dir(t0),
+		"Games":                       dir(t0),
+		"Games/Blizzard":              dir(t0),
+		"Games/Blizzard/Warcraft III": dir(t0),
+		"Games/Blizzard/Warcraft III/Frozen Throne":          dir(t0),
+		"Games/Blizzard/Warcraft III/Frozen Throne/war3.exe": file(),
+		"Tools":      dir(t0),
+		"Tools/a.go": file(),
+	}
+	fsys := countingFileSystem{countingFS{MapFS: tree, mu: &sync.Mutex{}, reads: map[string]int{}}}
+
+	cachePath := filepath.Join(t.TempDir(), "scan_cache.json")
+	s := NewScannerWith(memRegistry{}, fsys, nil)
+	s.cache = LoadScanCache(cachePath)
+
+	want := []string{filepath.FromSlash("/c/Games/Blizzard/Warcraft III/Frozen Throne")}
+	if got := s.FindConfigOrExeParallel(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("первое сканирование = %q, want %q", got, want)
+	}
+	if got := len(fsys.takeReads()); got != 6 {
+		t.Errorf("первое сканирование прочитало %d директорий, want 6", got)
+	}
+
+	// Кэш переживает перезапуск: загружаем его из файла заново
+	s.cache = LoadScanCache(cachePath)
+	if got := s.FindConfigOrExeParallel(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("повторное сканирование = %q, want %q", got, want)
+	}
+	if got := fsys.takeReads(); len(got) != 0 {
+		t.Errorf("повторное сканирование перечитало %q, want ничего", got)
+	}
+
+	// Добавляем новую установку: меняется только mtime папки Tools
+	t1 := t0.Add(time.Minute)
+	tree["Tools"] = dir(t1)
+	tree["Tools/W3"] = dir(t1)
+	tree["Tools/W3/config.lod.ini"] = file()
+
+	want = append(want, filepath.FromSlash("/c/Tools/W3"))
+	sort.Strings(want)
+	if got := s.FindConfigOrExeParallel(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("сканирование после изменения = %q, want %q", got, want)
+	}
+	if got, wantReads := fsys.takeReads(), []string{"Tools", "Tools/W3"}; !reflect.DeepEqual(got, wantReads) {
+		t.Errorf("сканирование после изменения перечитало %q, want %q", got, wantReads)
+	}
+}
+
+func TestScanCacheResetOnTargetChange(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	tree := fstest.MapFS{
+		".":              dir(t0),
+		"W3":             dir(t0),
+		"W3/war3.exe":    file(),
+		"W3/custom.exe":  file(),
+		"Other":          dir(t0),
+		"Other/game.exe": file(),
+	}
+	fsys := countingFileSystem{countingFS{MapFS: tree, mu: &sync.Mutex{}, reads: map[string]int{}}}
+
+	s := NewScannerWith(memRegistry{}, fsys, nil)
+	s.cache = NewScanCache()
+	s.FindConfigOrExeParallel(context.Background())
+	fsys.takeReads()
+
+	rules := DefaultScanRules()
+	rules.TargetPatterns = []string{"game.exe"}
+	s.rules = staticRules(rules)
+
+	want := []string{filepath.FromSlash("/c/Other")}
+	if got := s.FindConfigOrExeParallel(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Errorf("сканирование с новыми шаблонами = %q, want %q", got, want)
+	}
+	if got := len(fsys.takeReads()); got != 3 {
+		t.Errorf("после смены шаблонов прочитано %d директорий, want 3", got)
+	}
+}
+
+func TestScanRules(t *testing.T) {
+	rules := ScanRules{
+		MaxDepth:        2,
+		ExcludedFolders: []string{"skip"},
+		TargetPatterns:  []string{"war3*.exe"},
+	}
+	tree := fstest.MapFS{
+		"A/war3.exe":        file(),
+		"B/War3_126.exe":    file(),
+		"C/D/war3.exe":      file(),
+		"C/D/E/war3.exe":    file(),
+		"Skip/war3.exe":     file(),
+		"F/config.lod.ini":  file(),
+		"G/worldedit.exe":   file(),
+		"H/war3.exe.backup": file(),
+	}
+	s := NewScannerWith(memRegistry{}, mapFileSystem{"/c": tree}, nil)
+	s.rules = staticRules(rules)
+
+	var want []string
+	for _, p := range []string{"/c/A", "/c/B", "/c/C/D"} {
+		want = append(want, filepath.FromSlash(p))
+	}
+	if got := s.FindConfigOrExeParallel(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindConfigOrExeParallel() = %q, want %q", got, want)
+	}
+
+	// Явно заданные корни заменяют список дисков
+	rules.Roots = []string{"/c/C"}
+	s.rules = staticRules(rules)
+	want = []string{filepath.FromSlash("/c/C/D"), filepath.FromSlash("/c/C/D/E")}
+	if got := s.FindConfigOrExeParallel(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindConfigOrExeParallel() с корнем /c/C = %q, want %q", got, want)
+	}
+
+	if err := (ScanRules{MaxDepth: -1, TargetPatterns: []string{"x"}}).Validate(); err == nil {
+		t.Error("Validate() должен отклонять отрицательную глубину")
+	}
+	if err := (ScanRules{TargetPatterns: []string{"["}}).Validate(); err == nil {
+		t.Error("Validate() должен отклонять некорректный шаблон")
+	}
+	if err := DefaultScanRules().Validate(); err != nil {
+		t.Errorf("DefaultScanRules().Validate() = %v", err)
+	}
+}
+
+func TestScanCacheKeepsTimedOutRoot(t *testing.T) {
+	hung := make(chan struct{})
+	defer close(hung)
+
+	disks := delayFileSystem{
+		"/net": {MapFS: fstest.MapFS{"W3/war3.exe": file()}, block: hung},
+		"/c":   {MapFS: fstest.MapFS{"Games/W3/war3.exe": file()}},
+	}
+	rules := DefaultScanRules()
+	rules.RootTimeoutSec = 1
+	s := NewScannerWith(memRegistry{}, disks, nil)
+	s.rules = staticRules(rules)
+
+	// Кэш прошлого обхода: /net тогда отвечал, а /c/Old с тех пор удалили
+	s.cache = NewScanCache()
+	s.cache.targets = rules.targetsKey()
+	netDir := filepath.FromSlash("/net/W3")
+	oldDir := filepath.FromSlash("/c/Old")
+	s.cache.dirs[netDir] = cachedDir{Target: true}
+	s.cache.dirs[oldDir] = cachedDir{}
+
+	s.FindConfigOrExeParallel(context.Background())
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	if _, ok := s.cache.dirs[netDir]; !ok {
+		t.Errorf("директория %s корня с таймаутом удалена из кэша", netDir)
+	}
+	if _, ok := s.cache.dirs[oldDir]; ok {
+		t.Errorf("непосещённая директория %s полностью обойдённого корня осталась в кэше", oldDir)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package paths_scanner
+
+import "os"
+
+// getLogicalDrives возвращает корни для обхода на платформах без дисков Windows:
+// домашнюю директорию пользователя (там обычно лежат префиксы Wine).
+func getLogicalDrives() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{home}
+}
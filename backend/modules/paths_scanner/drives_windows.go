@@ -0,0 +1,20 @@
+//go:build windows
+
+package paths_scanner
+
+import (
+	"fmt"
+	"os"
+)
+
+// getLogicalDrives возвращает список логических дисков в Windows.
+func getLogicalDrives() []string {
+	var drives []string
+	for char := 'A'; char <= 'Z'; char++ {
+		drive := fmt.Sprintf("%c:\\", char)
+		if _, err := os.Stat(drive); err == nil {
+			drives = append(drives, drive)
+		}
+	}
+	return drives
+}
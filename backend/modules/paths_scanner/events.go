@@ -0,0 +1,34 @@
+package paths_scanner
+
+// Имена событий, которые сканер отправляет во фронтенд.
+const (
+	EventScanStarted   = "scan-started"    // данные: []string - корни обхода
+	EventScanProgress  = "scan-progress"   // данные: ScanProgress
+	EventScanPathFound = "scan-path-found" // данные: string - найденная папка
+	EventScanFinished  = "scan-finished"   // данные: ScanResult
+)
+
+// EventEmitter - минимальный интерфейс для отправки событий (application.EventManager в Wails).
+type EventEmitter interface {
+	Emit(name string, data ...any)
+}
+
+// noopEmitter используется, если отправлять события некуда.
+type noopEmitter struct{}
+
+func (noopEmitter) Emit(string, ...any) {}
+
+// ScanProgress - снимок состояния текущего сканирования.
+type ScanProgress struct {
+	CurrentRoot     string `json:"current_root"`     // корень, в котором был последний обход
+	RootsDone       int    `json:"roots_done"`       // сколько корней уже обойдено
+	RootsTotal      int    `json:"roots_total"`      // сколько корней всего
+	DirsVisited     int    `json:"dirs_visited"`     // сколько директорий посещено
+	CandidatesFound int    `json:"candidates_found"` // сколько папок с файлами игры найдено
+}
+
+// ScanResult - итог сканирования.
+type ScanResult struct {
+	Paths     []string `json:"paths"`
+	Cancelled bool     `json:"cancelled"`
+}
@@ -0,0 +1,57 @@
+package paths_scanner
+
+import (
+	"io/fs"
+	"os"
+)
+
+// FileSystem - абстракция файловой системы для сканера.
+// Обход выполняется через io/fs, поэтому в тестах можно подставить fstest.MapFS.
+type FileSystem interface {
+	// Roots возвращает корни для обхода (в Windows - логические диски).
+	Roots() []string
+	// Open возвращает fs.FS, корнем которой является root.
+	Open(root string) (fs.FS, error)
+	// Stat возвращает информацию о файле по абсолютному пути.
+	Stat(path string) (fs.FileInfo, error)
+	// Writable сообщает, можно ли создавать файлы в директории dir.
+	Writable(dir string) bool
+}
+
+// osFileSystem работает с настоящей файловой системой.
+type osFileSystem struct{}
+
+// OSFileSystem возвращает FileSystem поверх реальной файловой системы.
+func OSFileSystem() FileSystem {
+	return osFileSystem{}
+}
+
+func (osFileSystem) Roots() []string {
+	return getLogicalDrives()
+}
+
+func (osFileSystem) Open(root string) (fs.FS, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "open", Path: root, Err: fs.ErrInvalid}
+	}
+	return os.DirFS(root), nil
+}
+
+func (osFileSystem) Stat(path string) (fs.FileInfo, error) {
+	return os.Stat(path)
+}
+
+func (osFileSystem) Writable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".lce-write-test-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	f.Close()
+	_ = os.Remove(name)
+	return true
+}
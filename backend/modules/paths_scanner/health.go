@@ -0,0 +1,311 @@
+package paths_scanner
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// События фоновой проверки установок.
+const (
+	EventInstallMissing    = "install-missing"          // данные: string - папка установки пропала
+	EventInstallRestored   = "install-restored"         // данные: string - папка установки снова доступна
+	EventInstallFound      = "install-found"            // данные: string - найдена новая, ещё неизвестная установка
+	EventRelinkSuggested   = "install-relink-suggested" // данные: RelinkSuggestion
+	EventDrivesChanged     = "drives-changed"           // данные: DrivesChange
+	defaultHealthInterval  = 30 * time.Second
+	relinkSearchMaxResults = 3
+)
+
+// KnownInstalls возвращает пути установок, известных приложению (реализуется AppSettings).
+type KnownInstalls interface {
+	KnownInstallPaths() []string
+}
+
+// RelinkSuggestion - предложение перепривязать пропавшую установку к новой папке.
+type RelinkSuggestion struct {
+	OldPath    string   `json:"old_path"`
+	Candidates []string `json:"candidates"` // наиболее вероятная папка идёт первой
+}
+
+// DrivesChange - изменение списка дисков (например, подключили флешку).
+type DrivesChange struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// HealthReport - результат одной проверки.
+type HealthReport struct {
+	Missing     []string           `json:"missing"`     // известные установки, которых сейчас нет на диске
+	Found       []string           `json:"found"`       // новые установки на подключённых дисках
+	Suggestions []RelinkSuggestion `json:"suggestions"` // варианты перепривязки пропавших установок
+	CheckedAt   time.Time          `json:"checked_at"`
+}
+
+// HealthMonitor периодически проверяет известные установки и следит за подключением дисков.
+// Эта структура привязана к фронтенду Wails.
+type HealthMonitor struct {
+	scanner  *Scanner
+	known    KnownInstalls
+	interval time.Duration
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc // остановка фонового цикла, nil если он не запущен
+	done     chan struct{}
+	missing  map[string]bool     // установки, о пропаже которых уже сообщено
+	drives   map[string]struct{} // диски на момент прошлой проверки; nil - проверок ещё не было
+	reported map[string]bool     // новые установки, о которых уже сообщено
+}
+
+// NewHealthMonitor создаёт монитор поверх сканера. interval <= 0 означает значение по умолчанию.
+func NewHealthMonitor(scanner *Scanner, known KnownInstalls, interval time.Duration) *HealthMonitor {
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	return &HealthMonitor{
+		scanner:  scanner,
+		known:    known,
+		interval: interval,
+		missing:  make(map[string]bool),
+		reported: make(map[string]bool),
+	}
+}
+
+// StartMonitoring запускает фоновую проверку. Повторный вызов ничего не делает.
+// Эта функция привязана к фронтенду Wails.
+func (h *HealthMonitor) StartMonitoring() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan struct{})
+	go h.run(ctx, h.done)
+}
+
+// StopMonitoring останавливает фоновую проверку и ждёт завершения текущей итерации.
+// Эта функция привязана к фронтенду Wails.
+func (h *HealthMonitor) StopMonitoring() {
+	h.mu.Lock()
+	cancel, done := h.cancel, h.done
+	h.cancel, h.done = nil, nil
+	h.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (h *HealthMonitor) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.check(ctx, false)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckNow выполняет проверку немедленно, включая полный поиск новых установок.
+// Эта функция привязана к фронтенду Wails.
+func (h *HealthMonitor) CheckNow(ctx context.Context) HealthReport {
+	return h.check(ctx, true)
+}
+
+// check проверяет известные установки и диски. При full == true новые установки
+// ищутся на всех дисках, иначе - только на появившихся с прошлой проверки.
+func (h *HealthMonitor) check(ctx context.Context, full bool) HealthReport {
+	report := HealthReport{Missing: []string{}, Found: []string{}, Suggestions: []RelinkSuggestion{}, CheckedAt: time.Now()}
+	known := h.known.KnownInstallPaths()
+
+	// 1. Проверяем известные установки
+	var newlyMissing []string
+	for _, p := range known {
+		ok := h.scanner.isInstallPresent(p)
+		key := pathKey(p)
+
+		h.mu.Lock()
+		wasMissing := h.missing[key]
+		if ok {
+			delete(h.missing, key)
+		} else {
+			h.missing[key] = true
+		}
+		h.mu.Unlock()
+
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, p)
+			if !wasMissing {
+				log.Printf("Установка пропала: %s", p)
+				h.scanner.events.Emit(EventInstallMissing, p)
+				newlyMissing = append(newlyMissing, p)
+			}
+		case wasMissing:
+			log.Printf("Установка снова доступна: %s", p)
+			h.scanner.events.Emit(EventInstallRestored, p)
+		}
+	}
+
+	// 2. Следим за подключением и отключением дисков
+	rules := h.scanner.currentRules()
+	var rootsToScan []string
+	if change, changed := h.updateDrives(); changed {
+		log.Printf("Список дисков изменился: +%v -%v", change.Added, change.Removed)
+		h.scanner.events.Emit(EventDrivesChanged, change)
+		rootsToScan = change.Added
+	}
+	if full || len(newlyMissing) > 0 {
+		// Пропавшую установку могли перенести куда угодно - ищем везде (кэш делает это быстрым)
+		rootsToScan = h.scanner.scanRoots(rules)
+	}
+	if len(rootsToScan) == 0 || ctx.Err() != nil {
+		return report
+	}
+
+	// 3. Ищем установки на выбранных дисках, не мешая поиску, запущенному пользователем
+	st := newScanState(noopEmitter{}, len(rootsToScan))
+	if !h.scanner.walk(ctx, rootsToScan, rules, st, full) {
+		return report
+	}
+	candidates := unknownPaths(st.paths(), known)
+
+	for _, p := range candidates {
+		report.Found = append(report.Found, p)
+		h.mu.Lock()
+		alreadyReported := h.reported[pathKey(p)]
+		h.reported[pathKey(p)] = true
+		h.mu.Unlock()
+		if !alreadyReported {
+			log.Printf("Найдена новая установка: %s", p)
+			h.scanner.events.Emit(EventInstallFound, p)
+		}
+	}
+
+	// 4. Предлагаем перепривязать пропавшие установки
+	for _, missing := range report.Missing {
+		if best := rankRelinkCandidates(missing, candidates); len(best) > 0 {
+			suggestion := RelinkSuggestion{OldPath: missing, Candidates: best}
+			report.Suggestions = append(report.Suggestions, suggestion)
+			h.scanner.events.Emit(EventRelinkSuggested, suggestion)
+		}
+	}
+	return report
+}
+
+// updateDrives сравнивает текущие диски с предыдущей проверкой.
+func (h *HealthMonitor) updateDrives() (DrivesChange, bool) {
+	current := make(map[string]struct{})
+	for _, root := range h.scanner.fs.Roots() {
+		current[root] = struct{}{}
+	}
+
+	h.mu.Lock()
+	previous := h.drives
+	h.drives = current
+	h.mu.Unlock()
+
+	if previous == nil {
+		return DrivesChange{}, false // Первая проверка - запоминаем диски
+	}
+
+	change := DrivesChange{Added: []string{}, Removed: []string{}}
+	for root := range current {
+		if _, ok := previous[root]; !ok {
+			change.Added = append(change.Added, root)
+		}
+	}
+	for root := range previous {
+		if _, ok := current[root]; !ok {
+			change.Removed = append(change.Removed, root)
+		}
+	}
+	sort.Strings(change.Added)
+	sort.Strings(change.Removed)
+	return change, len(change.Added)+len(change.Removed) > 0
+}
+
+// isInstallPresent проверяет, что в папке есть war3.exe или config.lod.ini.
+func (s *Scanner) isInstallPresent(dir string) bool {
+	_, errConfig := s.fs.Stat(filepath.Join(dir, configFileName))
+	_, errExe := s.fs.Stat(filepath.Join(dir, gameExeName))
+	return errConfig == nil || errExe == nil
+}
+
+// pathKey нормализует путь для сравнения (без учёта регистра, как в Windows).
+func pathKey(p string) string {
+	return strings.ToLower(filepath.Clean(p))
+}
+
+// unknownPaths возвращает найденные пути, которых нет среди известных.
+func unknownPaths(found, known []string) []string {
+	knownSet := make(map[string]struct{}, len(known))
+	for _, p := range known {
+		knownSet[pathKey(p)] = struct{}{}
+	}
+	result := []string{}
+	for _, p := range found {
+		if _, ok := knownSet[pathKey(p)]; !ok {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// rankRelinkCandidates выбирает папки, в которые вероятнее всего перенесли установку old:
+// чем длиннее совпадающий хвост пути (…\Warcraft III\Frozen Throne), тем выше кандидат.
+// Папки без общего имени не предлагаются.
+func rankRelinkCandidates(old string, candidates []string) []string {
+	type scored struct {
+		path  string
+		score int
+	}
+	oldParts := splitPath(old)
+	var ranked []scored
+	for _, c := range candidates {
+		parts := splitPath(c)
+		score := 0
+		for i := 1; i <= len(oldParts) && i <= len(parts); i++ {
+			if !strings.EqualFold(oldParts[len(oldParts)-i], parts[len(parts)-i]) {
+				break
+			}
+			score++
+		}
+		if score > 0 {
+			ranked = append(ranked, scored{c, score})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+
+	result := []string{}
+	for i := 0; i < len(ranked) && i < relinkSearchMaxResults; i++ {
+		result = append(result, ranked[i].path)
+	}
+	return result
+}
+
+// splitPath разбивает путь на компоненты без корня.
+func splitPath(p string) []string {
+	p = filepath.ToSlash(filepath.Clean(p))
+	p = strings.TrimPrefix(p, filepath.ToSlash(filepath.VolumeName(p)))
+	var parts []string
+	for _, part := range strings.Split(p, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
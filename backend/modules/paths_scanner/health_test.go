@@ -0,0 +1,138 @@
+package paths_scanner
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// knownPaths - список известных установок для тестов.
+type knownPaths struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (k *knownPaths) KnownInstallPaths() []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return append([]string(nil), k.paths...)
+}
+
+func TestHealthMonitorMissingAndRestored(t *testing.T) {
+	disks := mapFileSystem{
+		"/c": {"Games/W3/war3.exe": file()},
+	}
+	events := &recordingEmitter{}
+	s := NewScannerWith(memRegistry{}, disks, events)
+	h := NewHealthMonitor(s, &knownPaths{paths: []string{"/c/Games/W3"}}, time.Hour)
+
+	if report := h.check(context.Background(), false); len(report.Missing) != 0 {
+		t.Fatalf("Missing = %q, want пусто", report.Missing)
+	}
+
+	delete(disks["/c"], "Games/W3/war3.exe")
+	for i := 0; i < 2; i++ {
+		report := h.check(context.Background(), false)
+		if want := []string{"/c/Games/W3"}; !reflect.DeepEqual(report.Missing, want) {
+			t.Fatalf("Missing = %q, want %q", report.Missing, want)
+		}
+	}
+	if got := events.get(EventInstallMissing); len(got) != 1 {
+		t.Errorf("событий %s: %d, want 1 (повторно о пропаже не сообщается)", EventInstallMissing, len(got))
+	}
+
+	disks["/c"]["Games/W3/war3.exe"] = file()
+	h.check(context.Background(), false)
+	if got := events.get(EventInstallRestored); !reflect.DeepEqual(got, []any{"/c/Games/W3"}) {
+		t.Errorf("события %s = %v", EventInstallRestored, got)
+	}
+}
+
+func TestHealthMonitorNewDrive(t *testing.T) {
+	disks := mapFileSystem{
+		"/c": {"Games/W3/war3.exe": file()},
+	}
+	events := &recordingEmitter{}
+	s := NewScannerWith(memRegistry{}, disks, events)
+	h := NewHealthMonitor(s, &knownPaths{paths: []string{"/c/Games/W3"}}, time.Hour)
+
+	h.check(context.Background(), false)
+	if got := events.get(EventInstallFound); len(got) != 0 {
+		t.Fatalf("без новых дисков найдено %v", got)
+	}
+
+	disks["/usb"] = fstest.MapFS{"Warcraft III/war3.exe": file()}
+	report := h.check(context.Background(), false)
+
+	want := []string{filepath.FromSlash("/usb/Warcraft III")}
+	if !reflect.DeepEqual(report.Found, want) {
+		t.Errorf("Found = %q, want %q", report.Found, want)
+	}
+	change, _ := events.get(EventDrivesChanged)[0].(DrivesChange)
+	if !reflect.DeepEqual(change.Added, []string{"/usb"}) {
+		t.Errorf("DrivesChange = %+v, want добавленный /usb", change)
+	}
+
+	// Повторная полная проверка не дублирует событие о той же установке
+	h.CheckNow(context.Background())
+	if got := events.get(EventInstallFound); len(got) != 1 {
+		t.Errorf("событий %s: %d, want 1", EventInstallFound, len(got))
+	}
+}
+
+func TestHealthMonitorRelinkSuggestion(t *testing.T) {
+	disks := mapFileSystem{
+		"/c": {"Games/Warcraft III/war3.exe": file()},
+		"/d": {
+			"Warcraft III/war3.exe": file(),
+			"Other/war3.exe":        file(),
+		},
+	}
+	known := &knownPaths{paths: []string{"/c/Games/Warcraft III", "/d/Other"}}
+	events := &recordingEmitter{}
+	s := NewScannerWith(memRegistry{}, disks, events)
+	h := NewHealthMonitor(s, known, time.Hour)
+	h.check(context.Background(), false)
+
+	// Папку перенесли с диска C на диск D
+	delete(disks["/c"], "Games/Warcraft III/war3.exe")
+	report := h.check(context.Background(), false)
+
+	want := []RelinkSuggestion{{
+		OldPath:    "/c/Games/Warcraft III",
+		Candidates: []string{filepath.FromSlash("/d/Warcraft III")},
+	}}
+	if !reflect.DeepEqual(report.Suggestions, want) {
+		t.Errorf("Suggestions = %+v, want %+v", report.Suggestions, want)
+	}
+	if got := events.get(EventRelinkSuggested); len(got) != 1 {
+		t.Errorf("событий %s: %d, want 1", EventRelinkSuggested, len(got))
+	}
+}
+
+func TestRankRelinkCandidates(t *testing.T) {
+	got := rankRelinkCandidates(`/old/Blizzard/Warcraft III`, []string{
+		"/new/Games/Warcraft III",
+		"/new/Blizzard/Warcraft III",
+		"/new/Unrelated",
+	})
+	want := []string{"/new/Blizzard/Warcraft III", "/new/Games/Warcraft III"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankRelinkCandidates() = %q, want %q", got, want)
+	}
+}
+
+func TestHealthMonitorStartStop(t *testing.T) {
+	s := NewScannerWith(memRegistry{}, mapFileSystem{"/c": {}}, nil)
+	h := NewHealthMonitor(s, &knownPaths{}, 10*time.Millisecond)
+
+	h.StartMonitoring()
+	h.StartMonitoring() // повторный запуск ничего не делает
+	time.Sleep(30 * time.Millisecond)
+	h.StopMonitoring()
+	h.StopMonitoring()
+}
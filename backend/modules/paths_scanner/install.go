@@ -0,0 +1,219 @@
+package paths_scanner
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Имена файлов, по которым определяется состав установки.
+const (
+	gameExeName    = "war3.exe"
+	configFileName = "config.lod.ini"
+)
+
+// loaderFiles - известные файлы загрузчика LoD (сравнение без учёта регистра).
+var loaderFiles = []string{"lod.exe", "lod.dll", "lodloader.exe"}
+
+// mapsSearchDepth - на какую глубину внутри папки Maps ищутся карты LoD.
+const mapsSearchDepth = 2
+
+// InstallInfo описывает найденную папку с игрой.
+type InstallInfo struct {
+	Path        string   `json:"path"`
+	HasExe      bool     `json:"has_exe"`      // есть war3.exe
+	GameVersion string   `json:"game_version"` // версия из ресурса war3.exe, например "1.26.0.6401"
+	LoDMaps     []string `json:"lod_maps"`     // карты LoD относительно папки игры
+	HasLoader   bool     `json:"has_loader"`   // есть файлы загрузчика LoD
+	HasConfig   bool     `json:"has_config"`   // есть config.lod.ini
+	ConfigValid bool     `json:"config_valid"` // config.lod.ini успешно разобран
+	ConfigError string   `json:"config_error"` // ошибка разбора config.lod.ini
+	Writable    bool     `json:"writable"`     // в папку можно записывать
+	Score       int      `json:"score"`        // чем больше, тем вероятнее это рабочая установка
+}
+
+// score вычисляет вес установки для сортировки списка на странице настроек.
+func (info *InstallInfo) score() int {
+	score := 0
+	if info.ConfigValid {
+		score += 8
+	} else if info.HasConfig {
+		score += 2
+	}
+	if len(info.LoDMaps) > 0 {
+		score += 4
+	}
+	if info.HasLoader {
+		score += 4
+	}
+	if info.HasExe {
+		score += 2
+		if info.GameVersion != "" {
+			score++
+		}
+	}
+	if info.Writable {
+		score++
+	}
+	return score
+}
+
+// describeInstall собирает сведения о папке dir.
+func (s *Scanner) describeInstall(dir string) InstallInfo {
+	dir = filepath.Clean(dir)
+	info := InstallInfo{Path: dir, LoDMaps: []string{}}
+
+	fsys, err := s.fs.Open(dir)
+	if err != nil {
+		log.Printf("Не удалось открыть папку %s: %v", dir, err)
+		return info
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		log.Printf("Не удалось прочитать папку %s: %v", dir, err)
+		return info
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if strings.EqualFold(entry.Name(), "Maps") {
+				info.LoDMaps = findLoDMaps(fsys, entry.Name())
+			}
+			continue
+		}
+
+		name := strings.ToLower(entry.Name())
+		switch {
+		case name == gameExeName:
+			info.HasExe = true
+			info.GameVersion = readGameVersion(fsys, entry.Name())
+		case name == configFileName:
+			info.HasConfig = true
+			if err := validateConfig(fsys, entry.Name()); err != nil {
+				info.ConfigError = err.Error()
+			} else {
+				info.ConfigValid = true
+			}
+		case isLoaderFile(name):
+			info.HasLoader = true
+		}
+	}
+
+	info.Writable = s.fs.Writable(dir)
+	info.Score = info.score()
+	return info
+}
+
+// readGameVersion возвращает версию war3.exe или пустую строку, если её не удалось определить.
+func readGameVersion(fsys fs.FS, name string) string {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		log.Printf("Не удалось прочитать %s: %v", name, err)
+		return ""
+	}
+	version, err := readPEFileVersion(data)
+	if err != nil {
+		log.Printf("Не удалось определить версию %s: %v", name, err)
+		return ""
+	}
+	return version
+}
+
+// validateConfig проверяет, что config.lod.ini читается и разбирается.
+func validateConfig(fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	_, err = ini.Load(data)
+	return err
+}
+
+func isLoaderFile(lowerName string) bool {
+	for _, loader := range loaderFiles {
+		if lowerName == loader {
+			return true
+		}
+	}
+	return false
+}
+
+// findLoDMaps ищет карты LoD (*.w3x с "lod" в имени) в папке mapsDir.
+func findLoDMaps(fsys fs.FS, mapsDir string) []string {
+	maps := []string{}
+	_ = fs.WalkDir(fsys, mapsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != mapsDir && strings.Count(p, "/") > mapsSearchDepth {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		name := strings.ToLower(d.Name())
+		if path.Ext(name) == ".w3x" && strings.Contains(name, "lod") {
+			maps = append(maps, filepath.FromSlash(p))
+		}
+		return nil
+	})
+	return maps
+}
+
+// rankInstalls сортирует установки по убыванию веса, при равенстве - по пути.
+func rankInstalls(installs []InstallInfo) {
+	sort.SliceStable(installs, func(i, j int) bool {
+		if installs[i].Score != installs[j].Score {
+			return installs[i].Score > installs[j].Score
+		}
+		return installs[i].Path < installs[j].Path
+	})
+}
+
+// DescribeInstall возвращает сведения об одной папке с игрой.
+// Эта функция привязана к фронтенду Wails.
+func (s *Scanner) DescribeInstall(dir string) InstallInfo {
+	return s.describeInstall(dir)
+}
+
+// DescribeInstalls возвращает сведения о переданных папках, отсортированные
+// так, что наиболее подходящая установка идёт первой.
+// Эта функция привязана к фронтенду Wails.
+func (s *Scanner) DescribeInstalls(paths []string) []InstallInfo {
+	installs := make([]InstallInfo, 0, len(paths))
+	for _, p := range paths {
+		installs = append(installs, s.describeInstall(p))
+	}
+	rankInstalls(installs)
+	return installs
+}
+
+// CheckAndFindInstalls ищет пути так же, как CheckAndFindPaths, и описывает каждую найденную папку.
+// Эта функция привязана к фронтенду Wails.
+func (s *Scanner) CheckAndFindInstalls(ctx context.Context) ([]InstallInfo, error) {
+	paths, err := s.CheckAndFindPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.DescribeInstalls(paths), nil
+}
+
+// DetectGameVersion возвращает версию war3.exe в папке dir на реальном диске
+// или пустую строку, если её не удалось определить.
+func DetectGameVersion(dir string) string {
+	fsys, err := OSFileSystem().Open(dir)
+	if err != nil {
+		return ""
+	}
+	if _, err := fs.Stat(fsys, gameExeName); err != nil {
+		return ""
+	}
+	return readGameVersion(fsys, gameExeName)
+}
@@ -0,0 +1,98 @@
+package paths_scanner
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// fixedFileInfo собирает фрагмент ресурса с VS_FIXEDFILEINFO для версии a.b.c.d.
+func fixedFileInfo(prefix int, a, b, c, d uint16) []byte {
+	data := make([]byte, prefix+52)
+	binary.LittleEndian.PutUint32(data[prefix:], vsFixedFileInfoSignature)
+	binary.LittleEndian.PutUint32(data[prefix+4:], 0x00010000)
+	binary.LittleEndian.PutUint32(data[prefix+8:], uint32(a)<<16|uint32(b))
+	binary.LittleEndian.PutUint32(data[prefix+12:], uint32(c)<<16|uint32(d))
+	return data
+}
+
+func TestParseFixedFileInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr bool
+	}{
+		{name: "в начале", data: fixedFileInfo(0, 1, 26, 0, 6401), want: "1.26.0.6401"},
+		{name: "со смещением", data: fixedFileInfo(40, 1, 24, 4, 6387), want: "1.24.4.6387"},
+		{name: "невыровненная сигнатура пропускается", data: fixedFileInfo(2, 1, 26, 0, 6401), wantErr: true},
+		{name: "обрезанная структура", data: fixedFileInfo(0, 1, 26, 0, 6401)[:10], wantErr: true},
+		{name: "нет сигнатуры", data: make([]byte, 64), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFixedFileInfo(tt.data)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseFixedFileInfo() = (%q, %v), want (%q, error %t)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDescribeInstalls(t *testing.T) {
+	disks := mapFileSystem{
+		"/c": fstest.MapFS{
+			"Full/war3.exe":                             file(),
+			"Full/config.lod.ini":                       {Data: []byte("[General]\nlanguage=en\n")},
+			"Full/LoD.exe":                              file(),
+			"Full/Maps/Download/DotA LoD v6.83s.w3x":    file(),
+			"Full/Maps/Download/DotA Allstars 6.83.w3x": file(),
+			"Broken/config.lod.ini":                     {Data: []byte("[General\nkey")},
+			"Broken/readonly":                           file(),
+			"ExeOnly/war3.exe":                          {Data: []byte("not a PE file")},
+		},
+	}
+	s := NewScannerWith(memRegistry{}, disks, nil)
+
+	got := s.DescribeInstalls([]string{"/c/ExeOnly", "/c/Broken", "/c/Full/", "/c/Missing"})
+
+	want := []InstallInfo{
+		{
+			Path:        "/c/Full",
+			HasExe:      true,
+			LoDMaps:     []string{"Maps/Download/DotA LoD v6.83s.w3x"},
+			HasLoader:   true,
+			HasConfig:   true,
+			ConfigValid: true,
+			Writable:    true,
+			Score:       19,
+		},
+		{Path: "/c/ExeOnly", HasExe: true, LoDMaps: []string{}, Writable: true, Score: 3},
+		{Path: "/c/Broken", HasConfig: true, LoDMaps: []string{}, Score: 2},
+		{Path: "/c/Missing", LoDMaps: []string{}},
+	}
+	for i := range want {
+		want[i].Path = filepath.FromSlash(want[i].Path)
+		for j := range want[i].LoDMaps {
+			want[i].LoDMaps[j] = filepath.FromSlash(want[i].LoDMaps[j])
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("DescribeInstalls() вернул %d записей, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].ConfigError != "" && want[i].Path == filepath.FromSlash("/c/Broken") {
+			want[i].ConfigError = got[i].ConfigError
+		}
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("DescribeInstalls()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got[2].ConfigError == "" {
+		t.Errorf("ожидалась ошибка разбора для %s", got[2].Path)
+	}
+}
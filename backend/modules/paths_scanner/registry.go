@@ -0,0 +1,20 @@
+package paths_scanner
+
+import "errors"
+
+// RegistryRoot - корневой раздел реестра, в котором ищется ключ.
+type RegistryRoot int
+
+const (
+	LocalMachine RegistryRoot = iota // HKEY_LOCAL_MACHINE
+	CurrentUser                      // HKEY_CURRENT_USER
+)
+
+// ErrRegistryUnavailable возвращается, если реестр недоступен (например, не в Windows).
+var ErrRegistryUnavailable = errors.New("реестр недоступен")
+
+// Registry - абстракция доступа к реестру, чтобы сканер можно было тестировать без Windows.
+type Registry interface {
+	// GetStringValue читает строковое значение name из ключа path в разделе root.
+	GetStringValue(root RegistryRoot, path, name string) (string, error)
+}
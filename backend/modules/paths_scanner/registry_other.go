@@ -0,0 +1,15 @@
+//go:build !windows
+
+package paths_scanner
+
+// noRegistry используется на платформах без реестра Windows.
+type noRegistry struct{}
+
+// defaultRegistry возвращает реестр текущей платформы.
+func defaultRegistry() Registry {
+	return noRegistry{}
+}
+
+func (noRegistry) GetStringValue(RegistryRoot, string, string) (string, error) {
+	return "", ErrRegistryUnavailable
+}
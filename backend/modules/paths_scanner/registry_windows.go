@@ -0,0 +1,38 @@
+//go:build windows
+
+package paths_scanner
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/registry" // Для доступа к реестру Windows
+)
+
+// windowsRegistry читает настоящий реестр Windows.
+type windowsRegistry struct{}
+
+// defaultRegistry возвращает реестр текущей платформы.
+func defaultRegistry() Registry {
+	return windowsRegistry{}
+}
+
+func (windowsRegistry) GetStringValue(root RegistryRoot, path, name string) (string, error) {
+	var key registry.Key
+	switch root {
+	case LocalMachine:
+		key = registry.LOCAL_MACHINE
+	case CurrentUser:
+		key = registry.CURRENT_USER
+	default:
+		return "", fmt.Errorf("неизвестный раздел реестра: %d", root)
+	}
+
+	k, err := registry.OpenKey(key, path, registry.READ)
+	if err != nil {
+		return "", err
+	}
+	defer k.Close()
+
+	value, _, err := k.GetStringValue(name)
+	return value, err
+}
@@ -0,0 +1,94 @@
+package paths_scanner
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ScanRules - настраиваемые правила поиска папок с игрой.
+// Хранятся в app_settings и передаются сканеру через RulesProvider.
+type ScanRules struct {
+	Roots           []string `json:"roots"`            // корни обхода; пусто - все логические диски
+	MaxDepth        int      `json:"max_depth"`        // максимальная глубина от корня
+	ExcludedFolders []string `json:"excluded_folders"` // имена папок, которые не обходятся (без учёта регистра)
+	TargetPatterns  []string `json:"target_patterns"`  // шаблоны имён целевых файлов (path.Match, без учёта регистра)
+	Workers         int      `json:"workers"`          // размер пула обхода; 0 - по числу ядер
+	RootTimeoutSec  int      `json:"root_timeout_sec"` // ограничение времени обхода одного корня; 0 - без ограничения
+}
+
+// DefaultScanRules возвращает правила по умолчанию.
+func DefaultScanRules() ScanRules {
+	return ScanRules{
+		Roots:           []string{},
+		MaxDepth:        5,
+		ExcludedFolders: []string{"Windows", "Users", "ProgramData", "System Volume Information", "$Recycle.Bin"},
+		TargetPatterns:  []string{"config.lod.ini", "war3.exe"},
+		Workers:         0,
+		RootTimeoutSec:  60,
+	}
+}
+
+// RulesProvider возвращает актуальные правила сканирования (реализуется AppSettings).
+type RulesProvider interface {
+	GetScanRules() ScanRules
+}
+
+// staticRules - RulesProvider с неизменными правилами.
+type staticRules ScanRules
+
+func (r staticRules) GetScanRules() ScanRules {
+	return ScanRules(r)
+}
+
+// Validate проверяет правила на корректность.
+func (r ScanRules) Validate() error {
+	if r.MaxDepth < 0 {
+		return fmt.Errorf("глубина поиска не может быть отрицательной: %d", r.MaxDepth)
+	}
+	if r.Workers < 0 {
+		return fmt.Errorf("количество воркеров не может быть отрицательным: %d", r.Workers)
+	}
+	if r.RootTimeoutSec < 0 {
+		return fmt.Errorf("таймаут обхода не может быть отрицательным: %d", r.RootTimeoutSec)
+	}
+	if len(r.TargetPatterns) == 0 {
+		return fmt.Errorf("не задано ни одного шаблона целевых файлов")
+	}
+	for _, pattern := range r.TargetPatterns {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return fmt.Errorf("некорректный шаблон '%s': %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// isExcluded проверяет, исключена ли папка из обхода.
+func (r ScanRules) isExcluded(folderName string) bool {
+	for _, excluded := range r.ExcludedFolders {
+		if strings.EqualFold(folderName, excluded) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTarget проверяет, является ли файл целевым.
+func (r ScanRules) isTarget(filename string) bool {
+	lowerFilename := strings.ToLower(filename)
+	for _, pattern := range r.TargetPatterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), lowerFilename); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// targetsKey - отпечаток шаблонов целевых файлов; при его смене кэш сканирования устаревает.
+func (r ScanRules) targetsKey() string {
+	patterns := make([]string, len(r.TargetPatterns))
+	for i, p := range r.TargetPatterns {
+		patterns[i] = strings.ToLower(p)
+	}
+	return strings.Join(patterns, "|")
+}
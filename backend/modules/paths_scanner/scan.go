@@ -0,0 +1,109 @@
+package paths_scanner
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// progressInterval - как часто отправлять событие прогресса во время сканирования.
+const progressInterval = 200 * time.Millisecond
+
+// scanState хранит общее состояние одного сканирования для всех горутин.
+type scanState struct {
+	events EventEmitter
+
+	mu       sync.Mutex
+	progress ScanProgress
+	seen     map[string]struct{}
+	found    []string
+	pinned   int // сколько первых путей в found не участвуют в сортировке
+}
+
+func newScanState(events EventEmitter, roots int) *scanState {
+	return &scanState{
+		events:   events,
+		progress: ScanProgress{RootsTotal: roots},
+		seen:     make(map[string]struct{}),
+	}
+}
+
+// visitDir учитывает посещённую директорию.
+func (st *scanState) visitDir(root string) {
+	st.mu.Lock()
+	st.progress.CurrentRoot = root
+	st.progress.DirsVisited++
+	st.mu.Unlock()
+}
+
+// rootDone отмечает завершение обхода одного корня.
+func (st *scanState) rootDone() {
+	st.mu.Lock()
+	st.progress.RootsDone++
+	st.mu.Unlock()
+}
+
+// addFound добавляет найденную папку и сразу сообщает о ней фронтенду.
+// Повторно найденные папки игнорируются.
+func (st *scanState) addFound(dir string) {
+	st.mu.Lock()
+	if _, exists := st.seen[dir]; exists {
+		st.mu.Unlock()
+		return
+	}
+	st.seen[dir] = struct{}{}
+	st.found = append(st.found, dir)
+	st.progress.CandidatesFound = len(st.found)
+	st.mu.Unlock()
+
+	st.events.Emit(EventScanPathFound, dir)
+}
+
+// snapshot возвращает копию текущего прогресса.
+func (st *scanState) snapshot() ScanProgress {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.progress
+}
+
+// pin закрепляет уже найденные пути в начале результата.
+func (st *scanState) pin() {
+	st.mu.Lock()
+	st.pinned = len(st.found)
+	st.mu.Unlock()
+}
+
+// paths возвращает копию найденных путей: сначала закреплённые,
+// затем остальные в алфавитном порядке, чтобы результат не зависел от порядка обхода.
+func (st *scanState) paths() []string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	paths := append([]string(nil), st.found...)
+	sort.Strings(paths[st.pinned:])
+	return paths
+}
+
+// startProgress запускает периодическую отправку прогресса.
+// Возвращаемая функция останавливает её, отправив итоговый прогресс.
+func (st *scanState) startProgress() (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				st.events.Emit(EventScanProgress, st.snapshot())
+			case <-done:
+				st.events.Emit(EventScanProgress, st.snapshot())
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -1,213 +1,225 @@
 package paths_scanner
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"log"
-	"os"
 	"path/filepath"
-	"strings"
 	"sync"
-
-	// Этот импорт все еще нужен для `LoadSettings` и `SaveSettings` в других функциях, но не в CheckAndFindPaths.
-	"golang.org/x/sys/windows/registry" // Для доступа к реестру Windows
 )
 
 // Scanner - это структура, которая будет привязана к фронтенду Wails.
 // Она содержит методы для поиска путей к файлам игры.
-type Scanner struct{}
-
-// NewScanner создает новый экземпляр Scanner.
-func NewScanner() *Scanner {
-	return &Scanner{}
+type Scanner struct {
+	registry Registry
+	fs       FileSystem
+	events   EventEmitter
+	rules    RulesProvider
+	cache    *ScanCache // nil - повторные сканирования не используют кэш
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc // отмена текущего сканирования, nil если сканирование не идёт
+	scanGen uint64             // номер последнего запущенного сканирования
+
+	walkSlot chan struct{} // занят на время обхода дисков: обходы выполняются по одному
 }
 
-// isTargetFile проверяет, является ли файл целевым (config.lod.ini или war3.exe).
-func isTargetFile(filename string) bool {
-	lowerFilename := strings.ToLower(filename)
-	return lowerFilename == "config.lod.ini" || lowerFilename == "war3.exe"
+// NewScanner создает новый экземпляр Scanner, работающий с реальным реестром и дисками.
+// События о ходе сканирования отправляются через events (обычно app.Event),
+// правила поиска берутся из rules, результаты обходов сохраняются в cache.
+func NewScanner(events EventEmitter, rules RulesProvider, cache *ScanCache) *Scanner {
+	s := NewScannerWith(defaultRegistry(), OSFileSystem(), events)
+	if rules != nil {
+		s.rules = rules
+	}
+	s.cache = cache
+	return s
 }
 
-// findFilesInFolder ищет целевые файлы в указанной папке с заданной глубиной.
-// Возвращает путь к папке, содержащей целевой файл, и булево значение, указывающее, найден ли он.
-func findFilesInFolder(root string, excludedFolders []string, maxDepth int) (string, bool) {
-	root = filepath.Clean(root) // Очищаем корневой путь
-	rootInfo, err := os.Stat(root)
-	if err != nil || !rootInfo.IsDir() {
-		return "", false
+// NewScannerWith создает Scanner с указанными реестром, файловой системой и получателем событий.
+// Используются правила по умолчанию и не используется кэш.
+func NewScannerWith(reg Registry, fsys FileSystem, events EventEmitter) *Scanner {
+	if events == nil {
+		events = noopEmitter{}
 	}
+	return &Scanner{
+		registry: reg,
+		fs:       fsys,
+		events:   events,
+		rules:    staticRules(DefaultScanRules()),
+		walkSlot: make(chan struct{}, 1),
+	}
+}
 
-	foundPath := ""
-	found := false
+// registryKeyPath - ключ реестра, в котором Warcraft III хранит путь установки.
+const registryKeyPath = `SOFTWARE\Blizzard Entertainment\Warcraft III`
 
-	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+// findPathInRegistry ищет путь установки Warcraft III в реестре Windows.
+// Сначала проверяется HKEY_LOCAL_MACHINE, затем HKEY_CURRENT_USER.
+func (s *Scanner) findPathInRegistry() (string, bool) {
+	for _, root := range []RegistryRoot{LocalMachine, CurrentUser} {
+		installPath, err := s.registry.GetStringValue(root, registryKeyPath, "InstallPath")
 		if err != nil {
-			// Логируем ошибку, но продолжаем обход
-			log.Printf("Ошибка доступа к пути %s: %v", path, err)
-			return nil
-		}
-
-		// Вычисляем текущую глубину относительно корневого каталога
-		depthFromRoot := 0
-		if path != root {
-			// Количество разделителей пути в части после корня
-			depthFromRoot = strings.Count(path[len(root):], string(os.PathSeparator))
-			if d.IsDir() {
-				// Если это директория и она не является самим корнем, увеличиваем глубину на 1
-				// Пример: C:\ -> depth 0; C:\Games -> depth 1; C:\Games\Warcraft -> depth 2
-				if len(path[len(root):]) > 0 && path[len(root)] == os.PathSeparator {
-					depthFromRoot = strings.Count(path[len(root):], string(os.PathSeparator))
-				} else { // Handle direct subfolders without a leading separator in path[len(root):]
-					depthFromRoot = 1
-				}
+			if !errors.Is(err, ErrRegistryUnavailable) {
+				log.Printf("Не удалось прочитать InstallPath из реестра: %v", err)
 			}
+			continue
+		}
+		if installPath == "" {
+			continue
 		}
 
-		if d.IsDir() {
-			if depthFromRoot > maxDepth {
-				return filepath.SkipDir // Пропускаем папки, находящиеся глубже maxDepth
-			}
+		// Проверяем наличие config.lod.ini или war3.exe в найденном пути
+		_, errConfig := s.fs.Stat(filepath.Join(installPath, "config.lod.ini"))
+		_, errExe := s.fs.Stat(filepath.Join(installPath, "war3.exe"))
 
-			folderName := d.Name()
-			for _, excluded := range excludedFolders {
-				if strings.EqualFold(folderName, excluded) {
-					return filepath.SkipDir // Пропускаем исключенные папки
-				}
-			}
-		} else if d.Type().IsRegular() && isTargetFile(d.Name()) {
-			foundPath = filepath.Dir(path)
-			found = true
-			return filepath.SkipAll // Найден целевой файл, останавливаем обход
+		if errConfig == nil || errExe == nil {
+			return filepath.Clean(installPath), true
 		}
-		return nil
-	})
-
-	if err != nil {
-		log.Printf("Ошибка при обходе директории %s: %v", root, err)
 	}
 
-	return foundPath, found
+	return "", false
 }
 
-// getLogicalDrives возвращает список логических дисков в Windows.
-func getLogicalDrives() []string {
-	var drives []string
-	for char := 'A'; char <= 'Z'; char++ {
-		drive := fmt.Sprintf("%c:\\", char)
-		if _, err := os.Stat(drive); err == nil {
-			drives = append(drives, drive)
+// beginScan отменяет предыдущее сканирование (если оно идёт) и возвращает контекст нового.
+func (s *Scanner) beginScan(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.cancel = cancel
+	s.scanGen++
+	gen := s.scanGen
+	s.mu.Unlock()
+
+	return ctx, func() {
+		s.mu.Lock()
+		cancel()
+		if s.scanGen == gen {
+			s.cancel = nil
 		}
+		s.mu.Unlock()
 	}
-	return drives
 }
 
-// findPathInRegistry ищет путь установки Warcraft III в реестре Windows.
-func findPathInRegistry() (string, bool) {
-	regPath := `SOFTWARE\Blizzard Entertainment\Warcraft III`
-
-	// HKEY_LOCAL_MACHINE
-	if k, err := registry.OpenKey(registry.LOCAL_MACHINE, regPath, registry.READ); err == nil {
-		if installPath, _, err := k.GetStringValue("InstallPath"); err == nil {
-			pathConfig := filepath.Join(installPath, "config.lod.ini")
-			pathExe := filepath.Join(installPath, "war3.exe")
-
-			// Проверяем наличие config.lod.ini или war3.exe в найденном пути
-			_, errConfig := os.Stat(pathConfig)
-			_, errExe := os.Stat(pathExe)
-
-			if errConfig == nil || errExe == nil { // Исправленное условие
-				k.Close()
-				return installPath, true
-			}
-		}
-		k.Close()
+// CancelScan прерывает текущее сканирование. Уже найденные пути будут возвращены.
+// Эта функция привязана к фронтенду Wails.
+func (s *Scanner) CancelScan() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		log.Println("Сканирование отменено пользователем")
+		s.cancel()
 	}
+}
 
-	// HKEY_CURRENT_USER
-	if k, err := registry.OpenKey(registry.CURRENT_USER, regPath, registry.READ); err == nil {
-		if installPath, _, err := k.GetStringValue("InstallPath"); err == nil {
-			pathConfig := filepath.Join(installPath, "config.lod.ini")
-			pathExe := filepath.Join(installPath, "war3.exe")
+// currentRules возвращает проверенные правила сканирования.
+func (s *Scanner) currentRules() ScanRules {
+	rules := s.rules.GetScanRules()
+	if err := rules.Validate(); err != nil {
+		log.Printf("Некорректные правила сканирования, используются правила по умолчанию: %v", err)
+		rules = DefaultScanRules()
+	}
+	return rules
+}
 
-			// Проверяем наличие config.lod.ini или war3.exe в найденном пути
-			_, errConfig := os.Stat(pathConfig)
-			_, errExe := os.Stat(pathExe)
+// scanRoots возвращает корни обхода: из правил или все диски.
+func (s *Scanner) scanRoots(rules ScanRules) []string {
+	if len(rules.Roots) > 0 {
+		return rules.Roots
+	}
+	return s.fs.Roots()
+}
 
-			if errConfig == nil || errExe == nil { // Исправленное условие
-				k.Close()
-				return installPath, true
-			}
+// walk обходит корни с использованием кэша. Обходы выполняются строго по одному,
+// так как кэш хранит состояние текущего обхода. Если другой обход уже идёт, walk
+// при wait == false сразу возвращает false, а при wait == true ждёт его окончания
+// или отмены ctx (тогда тоже возвращается false).
+func (s *Scanner) walk(ctx context.Context, roots []string, rules ScanRules, st *scanState, wait bool) bool {
+	if wait {
+		select {
+		case s.walkSlot <- struct{}{}:
+		case <-ctx.Done():
+			return false
+		}
+	} else {
+		select {
+		case s.walkSlot <- struct{}{}:
+		default:
+			return false
 		}
-		k.Close()
 	}
+	defer func() { <-s.walkSlot }()
 
-	return "", false
+	if s.cache != nil {
+		s.cache.begin(rules)
+	}
+	complete := s.walkRoots(ctx, roots, rules, st)
+	if s.cache != nil {
+		if err := s.cache.finish(complete); err != nil {
+			log.Printf("Не удалось сохранить кэш сканирования: %v", err)
+		}
+	}
+	return true
 }
 
-// FindConfigOrExeParallel параллельно ищет пути к файлам config.lod.ini или war3.exe на всех логических дисках.
-// Эта функция привязана к фронтенду Wails.
-func (s *Scanner) FindConfigOrExeParallel() []string {
-	excludedFolders := []string{"Windows", "Users", "ProgramData", "System Volume Information"}
-	drives := getLogicalDrives()
-
-	var wg sync.WaitGroup
-	results := make(chan string, len(drives)) // Буферизованный канал для результатов
-
-	for _, drive := range drives {
-		wg.Add(1)
-		go func(drive string) {
-			defer wg.Done()
-			if path, found := findFilesInFolder(drive, excludedFolders, 3); found {
-				results <- path
-			}
-		}(drive)
-	}
+// runScan выполняет одно сканирование всех корней. Пути из seed добавляются
+// к результату первыми (например, путь из реестра).
+func (s *Scanner) runScan(ctx context.Context, seed ...string) ScanResult {
+	ctx, done := s.beginScan(ctx)
+	defer done()
 
-	wg.Wait()      // Ждем завершения всех горутин
-	close(results) // Закрываем канал после завершения всех записей
+	rules := s.currentRules()
+	drives := s.scanRoots(rules)
 
-	uniquePaths := make(map[string]struct{})
-	var foundPaths []string
-	for path := range results {
-		if _, exists := uniquePaths[path]; !exists {
-			uniquePaths[path] = struct{}{}
-			foundPaths = append(foundPaths, path)
-		}
+	st := newScanState(s.events, len(drives))
+	s.events.Emit(EventScanStarted, drives)
+	for _, p := range seed {
+		st.addFound(p)
 	}
-	return foundPaths
+	st.pin()
+
+	stopProgress := st.startProgress()
+	s.walk(ctx, drives, rules, st, true)
+	stopProgress()
+
+	result := ScanResult{Paths: st.paths(), Cancelled: ctx.Err() != nil}
+	s.events.Emit(EventScanFinished, result)
+	return result
+}
+
+// FindConfigOrExeParallel параллельно ищет пути к файлам config.lod.ini или war3.exe на всех логических дисках.
+// Во время поиска отправляются события scan-progress и scan-path-found.
+// Эта функция привязана к фронтенду Wails.
+func (s *Scanner) FindConfigOrExeParallel(ctx context.Context) []string {
+	return s.runScan(ctx).Paths
 }
 
 // CheckAndFindPaths - основная функция для поиска путей.
+// Сканирование можно прервать через CancelScan или отменив вызов на фронтенде,
+// в этом случае возвращаются пути, найденные до отмены.
 // Эта функция будет привязана к фронтенду Wails.
-func (s *Scanner) CheckAndFindPaths() ([]string, error) {
+func (s *Scanner) CheckAndFindPaths(ctx context.Context) ([]string, error) {
 	log.Println("=== Начало CheckAndFindPaths ===")
 	log.Println("Выполняем поиск путей")
 
-	// Находим путь в реестре
-	registryPath, foundInRegistry := findPathInRegistry()
+	// Находим путь в реестре, он идёт первым в результате
+	var seed []string
+	registryPath, foundInRegistry := s.findPathInRegistry()
 	log.Printf("Путь из реестра: \"%s\", найдено: %t\n", registryPath, foundInRegistry)
-
-	// Параллельно ищем пути на дисках
-	foundFolders := s.FindConfigOrExeParallel()
-	log.Printf("Найденные папки: %+v\n", foundFolders)
-
-	// Объединяем все найденные пути
-	combinedPathsMap := make(map[string]struct{})
 	if foundInRegistry {
-		combinedPathsMap[registryPath] = struct{}{}
-	}
-	for _, p := range foundFolders {
-		combinedPathsMap[p] = struct{}{}
+		seed = append(seed, registryPath)
 	}
 
-	var resultPaths []string
-	for p := range combinedPathsMap {
-		resultPaths = append(resultPaths, p)
-	}
+	// Параллельно ищем пути на дисках
+	result := s.runScan(ctx, seed...)
 
-	log.Printf("Всего найдено уникальных путей: %+v\n", resultPaths)
+	log.Printf("Всего найдено уникальных путей: %+v (отменено: %t)\n", result.Paths, result.Cancelled)
 	log.Println("=== Конец CheckAndFindPaths ===")
 
-	return resultPaths, nil
+	return result.Paths, nil
 }
 
 // contains - вспомогательная функция для проверки, содержит ли слайс строку
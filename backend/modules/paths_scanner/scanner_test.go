@@ -0,0 +1,429 @@
+package paths_scanner
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// memRegistry - реестр в памяти: ключ "root|path|name" -> значение.
+type memRegistry map[string]string
+
+func (r memRegistry) GetStringValue(root RegistryRoot, path, name string) (string, error) {
+	if r == nil {
+		return "", ErrRegistryUnavailable
+	}
+	v, ok := r[registryEntry(root, name)]
+	if !ok || path != registryKeyPath {
+		return "", fs.ErrNotExist
+	}
+	return v, nil
+}
+
+func registryEntry(root RegistryRoot, name string) string {
+	return string(rune('0'+root)) + "|" + name
+}
+
+// mapFileSystem - набор дисков, каждый из которых представлен fstest.MapFS.
+type mapFileSystem map[string]fstest.MapFS
+
+func (m mapFileSystem) Roots() []string {
+	roots := make([]string, 0, len(m))
+	for root := range m {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+func (m mapFileSystem) Open(root string) (fs.FS, error) {
+	root = filepath.ToSlash(filepath.Clean(root))
+	if fsys, ok := m[root]; ok {
+		return fsys, nil
+	}
+	for drive, fsys := range m {
+		if rel, ok := strings.CutPrefix(root, drive+"/"); ok {
+			if info, err := fs.Stat(fsys, rel); err != nil || !info.IsDir() {
+				return nil, fs.ErrNotExist
+			}
+			return fs.Sub(fsys, rel)
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+func (m mapFileSystem) Stat(path string) (fs.FileInfo, error) {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for root, fsys := range m {
+		rel, ok := strings.CutPrefix(path, root+"/")
+		if ok {
+			return fs.Stat(fsys, rel)
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+// Writable считает доступными для записи папки, в которых нет файла "readonly".
+func (m mapFileSystem) Writable(dir string) bool {
+	_, err := m.Stat(filepath.Join(dir, "readonly"))
+	return err != nil
+}
+
+func file() *fstest.MapFile { return &fstest.MapFile{} }
+
+func TestWalkRoots(t *testing.T) {
+	tests := []struct {
+		name string
+		tree fstest.MapFS
+		want []string
+	}{
+		{
+			name: "в корне диска",
+			tree: fstest.MapFS{"war3.exe": file()},
+			want: []string{"/c"},
+		},
+		{
+			name: "на максимальной глубине",
+			tree: fstest.MapFS{"Games/Blizzard/Warcraft III/war3.exe": file()},
+			want: []string{"/c/Games/Blizzard/Warcraft III"},
+		},
+		{
+			name: "глубже максимальной глубины",
+			tree: fstest.MapFS{"Games/Blizzard/Warcraft III/Frozen Throne/war3.exe": file()},
+		},
+		{
+			name: "регистр имени файла не важен",
+			tree: fstest.MapFS{"Games/W3/Config.LoD.ini": file()},
+			want: []string{"/c/Games/W3"},
+		},
+		{
+			name: "исключённая папка",
+			tree: fstest.MapFS{"windows/w3/war3.exe": file()},
+		},
+		{
+			name: "директория с именем целевого файла",
+			tree: fstest.MapFS{"war3.exe/readme.txt": file()},
+		},
+		{
+			name: "пустой диск",
+			tree: fstest.MapFS{},
+		},
+		{
+			name: "несколько установок на одном диске",
+			tree: fstest.MapFS{
+				"Games/W3/war3.exe":        file(),
+				"Games/W3/config.lod.ini":  file(),
+				"Games/W3 LoD/war3.exe":    file(),
+				"Games/W3 LoD/Maps/a.w3x":  file(),
+				"Games/W3 LoD/Maps/b.w3x":  file(),
+				"Games/W3 LoD/Maps/war3.x": file(),
+			},
+			want: []string{"/c/Games/W3", "/c/Games/W3 LoD"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScannerWith(memRegistry{}, mapFileSystem{"/c": tt.tree}, nil)
+			rules := DefaultScanRules()
+			rules.MaxDepth = 3
+			st := newScanState(noopEmitter{}, 1)
+			s.walkRoots(context.Background(), []string{"/c"}, rules, st)
+			found := st.paths()
+			var want []string
+			for _, p := range tt.want {
+				want = append(want, filepath.FromSlash(p))
+			}
+			if !reflect.DeepEqual(found, want) {
+				t.Errorf("walkRoots() found %q, want %q", found, want)
+			}
+		})
+	}
+}
+
+func TestFindPathInRegistry(t *testing.T) {
+	disk := mapFileSystem{
+		"/c": fstest.MapFS{
+			"Warcraft III/war3.exe":      file(),
+			"LoD/config.lod.ini":         file(),
+			"Empty/readme.txt":           file(),
+			"Other Game/other_game.exe":  file(),
+			"Warcraft III/Maps/dota.w3x": file(),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		registry  memRegistry
+		wantPath  string
+		wantFound bool
+	}{
+		{
+			name:      "реестр недоступен",
+			registry:  nil,
+			wantFound: false,
+		},
+		{
+			name:      "ключ отсутствует",
+			registry:  memRegistry{},
+			wantFound: false,
+		},
+		{
+			name:      "HKLM",
+			registry:  memRegistry{registryEntry(LocalMachine, "InstallPath"): "/c/Warcraft III"},
+			wantPath:  "/c/Warcraft III",
+			wantFound: true,
+		},
+		{
+			name:      "HKCU, если в HKLM ничего нет",
+			registry:  memRegistry{registryEntry(CurrentUser, "InstallPath"): "/c/LoD"},
+			wantPath:  "/c/LoD",
+			wantFound: true,
+		},
+		{
+			name: "HKCU, если путь из HKLM пустой",
+			registry: memRegistry{
+				registryEntry(LocalMachine, "InstallPath"): "/c/Empty",
+				registryEntry(CurrentUser, "InstallPath"):  "/c/Warcraft III",
+			},
+			wantPath:  "/c/Warcraft III",
+			wantFound: true,
+		},
+		{
+			name: "HKLM имеет приоритет",
+			registry: memRegistry{
+				registryEntry(LocalMachine, "InstallPath"): "/c/LoD",
+				registryEntry(CurrentUser, "InstallPath"):  "/c/Warcraft III",
+			},
+			wantPath:  "/c/LoD",
+			wantFound: true,
+		},
+		{
+			name: "оба пути без файлов игры",
+			registry: memRegistry{
+				registryEntry(LocalMachine, "InstallPath"): "/c/Other Game",
+				registryEntry(CurrentUser, "InstallPath"):  "/c/Missing",
+			},
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScannerWith(tt.registry, disk, nil)
+			gotPath, gotFound := s.findPathInRegistry()
+			if gotFound != tt.wantFound || gotPath != filepath.FromSlash(tt.wantPath) {
+				t.Errorf("findPathInRegistry() = (%q, %t), want (%q, %t)", gotPath, gotFound, tt.wantPath, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestCheckAndFindPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry memRegistry
+		disks    mapFileSystem
+		want     []string
+	}{
+		{
+			name:     "ничего не найдено",
+			registry: memRegistry{},
+			disks:    mapFileSystem{"/c": {}, "/d": {}},
+			want:     nil,
+		},
+		{
+			name:     "по одному пути на диск",
+			registry: memRegistry{},
+			disks: mapFileSystem{
+				"/c": {"Games/W3/war3.exe": file()},
+				"/d": {"Warcraft III/config.lod.ini": file()},
+			},
+			want: []string{"/c/Games/W3", "/d/Warcraft III"},
+		},
+		{
+			name:     "путь из реестра идёт первым и не дублируется",
+			registry: memRegistry{registryEntry(LocalMachine, "InstallPath"): "/d/Warcraft III/"},
+			disks: mapFileSystem{
+				"/c": {"Games/W3/war3.exe": file()},
+				"/d": {"Warcraft III/war3.exe": file()},
+			},
+			want: []string{"/d/Warcraft III", "/c/Games/W3"},
+		},
+		{
+			name:     "путь только из реестра",
+			registry: memRegistry{registryEntry(CurrentUser, "InstallPath"): "/d/Deep/A/B/C/W3"},
+			disks: mapFileSystem{
+				"/d": {"Deep/A/B/C/W3/war3.exe": file()},
+			},
+			want: []string{"/d/Deep/A/B/C/W3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScannerWith(tt.registry, tt.disks, nil)
+			got, err := s.CheckAndFindPaths(context.Background())
+			if err != nil {
+				t.Fatalf("CheckAndFindPaths() error = %v", err)
+			}
+			var want []string
+			for _, p := range tt.want {
+				want = append(want, filepath.FromSlash(p))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("CheckAndFindPaths() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// recordingEmitter запоминает отправленные события.
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events map[string][]any
+	onEmit func(name string, data any)
+}
+
+func (r *recordingEmitter) Emit(name string, data ...any) {
+	r.mu.Lock()
+	if r.events == nil {
+		r.events = make(map[string][]any)
+	}
+	var payload any
+	if len(data) > 0 {
+		payload = data[0]
+	}
+	r.events[name] = append(r.events[name], payload)
+	onEmit := r.onEmit
+	r.mu.Unlock()
+
+	if onEmit != nil {
+		onEmit(name, payload)
+	}
+}
+
+func (r *recordingEmitter) get(name string) []any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[name]
+}
+
+func TestScanEvents(t *testing.T) {
+	events := &recordingEmitter{}
+	s := NewScannerWith(memRegistry{}, mapFileSystem{
+		"/c": {"Games/W3/war3.exe": file(), "Tools/readme.txt": file()},
+		"/d": {"Warcraft III/config.lod.ini": file()},
+	}, events)
+
+	paths := s.FindConfigOrExeParallel(context.Background())
+
+	if got := len(events.get(EventScanStarted)); got != 1 {
+		t.Errorf("%s отправлено %d раз, want 1", EventScanStarted, got)
+	}
+
+	var streamed []string
+	for _, p := range events.get(EventScanPathFound) {
+		streamed = append(streamed, p.(string))
+	}
+	sort.Strings(streamed)
+	if !reflect.DeepEqual(streamed, paths) {
+		t.Errorf("%s = %q, want %q", EventScanPathFound, streamed, paths)
+	}
+
+	progress := events.get(EventScanProgress)
+	if len(progress) == 0 {
+		t.Fatalf("%s не отправлено", EventScanProgress)
+	}
+	last := progress[len(progress)-1].(ScanProgress)
+	want := ScanProgress{RootsDone: 2, RootsTotal: 2, DirsVisited: 6, CandidatesFound: 2}
+	last.CurrentRoot = ""
+	if last != want {
+		t.Errorf("последний прогресс = %+v, want %+v", last, want)
+	}
+
+	finished := events.get(EventScanFinished)
+	if len(finished) != 1 {
+		t.Fatalf("%s отправлено %d раз, want 1", EventScanFinished, len(finished))
+	}
+	if res := finished[0].(ScanResult); res.Cancelled || !reflect.DeepEqual(res.Paths, paths) {
+		t.Errorf("%s = %+v, want paths %q", EventScanFinished, res, paths)
+	}
+}
+
+func TestCancelScan(t *testing.T) {
+	tree := fstest.MapFS{"A/war3.exe": file()}
+	for _, dir := range []string{"B", "C", "D", "E", "F"} {
+		tree[dir+"/sub/readme.txt"] = file()
+		tree[dir+"/war3.exe"] = file()
+	}
+
+	events := &recordingEmitter{}
+	s := NewScannerWith(memRegistry{}, mapFileSystem{"/c": tree}, events)
+	// Один воркер, чтобы после отмены не успели завершиться параллельные задачи
+	rules := DefaultScanRules()
+	rules.Workers = 1
+	s.rules = staticRules(rules)
+	// Отменяем сканирование сразу после первой найденной папки
+	events.onEmit = func(name string, data any) {
+		if name == EventScanPathFound {
+			s.CancelScan()
+		}
+	}
+
+	got, err := s.CheckAndFindPaths(context.Background())
+	if err != nil {
+		t.Fatalf("CheckAndFindPaths() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("CheckAndFindPaths() = %q, want один путь", got)
+	}
+
+	finished := events.get(EventScanFinished)
+	if len(finished) != 1 || !finished[0].(ScanResult).Cancelled {
+		t.Errorf("%s = %+v, want cancelled", EventScanFinished, finished)
+	}
+
+	// После отмены сканер снова готов к работе
+	events.onEmit = nil
+	got, _ = s.CheckAndFindPaths(context.Background())
+	if len(got) != 6 {
+		t.Errorf("повторное сканирование нашло %d путей, want 6", len(got))
+	}
+}
+
+func TestCancelScanWhileHealthWalkRuns(t *testing.T) {
+	events := &recordingEmitter{}
+	s := NewScannerWith(memRegistry{}, mapFileSystem{"/c": fstest.MapFS{"A/war3.exe": file()}}, events)
+	started := make(chan struct{})
+	events.onEmit = func(name string, data any) {
+		if name == EventScanStarted {
+			close(started)
+		}
+	}
+
+	// Обход занят фоновой проверкой установок, которая не торопится завершаться
+	s.walkSlot <- struct{}{}
+	defer func() { <-s.walkSlot }()
+
+	result := make(chan ScanResult, 1)
+	go func() { result <- s.runScan(context.Background()) }()
+	<-started
+	s.CancelScan()
+
+	select {
+	case res := <-result:
+		if !res.Cancelled {
+			t.Errorf("runScan() = %+v, want cancelled", res)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("CancelScan не прервал ожидание фонового обхода")
+	}
+}
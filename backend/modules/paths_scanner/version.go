@@ -0,0 +1,58 @@
+package paths_scanner
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// vsFixedFileInfoSignature - сигнатура структуры VS_FIXEDFILEINFO в ресурсе версии.
+const vsFixedFileInfoSignature = 0xFEEF04BD
+
+// errNoVersionInfo возвращается, если в файле нет ресурса версии.
+var errNoVersionInfo = errors.New("ресурс версии не найден")
+
+// readPEFileVersion читает версию файла (FileVersion) из ресурса VS_VERSIONINFO исполняемого файла.
+// Возвращает строку вида "1.26.0.6401".
+func readPEFileVersion(data []byte) (string, error) {
+	f, err := pe.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("не удалось разобрать PE-файл: %w", err)
+	}
+	defer f.Close()
+
+	rsrc := f.Section(".rsrc")
+	if rsrc == nil {
+		return "", errNoVersionInfo
+	}
+	section, err := rsrc.Data()
+	if err != nil {
+		return "", fmt.Errorf("не удалось прочитать секцию ресурсов: %w", err)
+	}
+	return parseFixedFileInfo(section)
+}
+
+// parseFixedFileInfo ищет VS_FIXEDFILEINFO в данных ресурсов и возвращает версию файла.
+// Структура выровнена по 4 байта и начинается с сигнатуры 0xFEEF04BD.
+func parseFixedFileInfo(data []byte) (string, error) {
+	var sig [4]byte
+	binary.LittleEndian.PutUint32(sig[:], vsFixedFileInfoSignature)
+
+	for offset := 0; ; {
+		i := bytes.Index(data[offset:], sig[:])
+		if i < 0 {
+			return "", errNoVersionInfo
+		}
+		pos := offset + i
+		offset = pos + 1
+		// dwSignature, dwStrucVersion, dwFileVersionMS, dwFileVersionLS
+		if pos%4 != 0 || len(data) < pos+16 {
+			continue
+		}
+		ms := binary.LittleEndian.Uint32(data[pos+8:])
+		ls := binary.LittleEndian.Uint32(data[pos+12:])
+		return fmt.Sprintf("%d.%d.%d.%d", ms>>16, ms&0xFFFF, ls>>16, ls&0xFFFF), nil
+	}
+}
@@ -0,0 +1,264 @@
+package paths_scanner
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// defaultWorkers возвращает размер пула обхода, если он не задан в правилах.
+// Обход упирается в ввод-вывод, поэтому воркеров больше, чем ядер.
+func defaultWorkers() int {
+	n := runtime.NumCPU() * 2
+	if n < 4 {
+		n = 4
+	}
+	return n
+}
+
+// rootWalk - состояние обхода одного корня.
+type rootWalk struct {
+	path   string
+	ctx    context.Context // отменяется по таймауту корня или при отмене сканирования
+	cancel context.CancelFunc
+	fsys   fs.FS // открывается первой задачей корня
+
+	pending int // задачи корня в очереди и в работе (под dirQueue.mu)
+}
+
+// dirTask - директория, которую нужно прочитать.
+type dirTask struct {
+	root  *rootWalk
+	rel   string // путь внутри root.fsys
+	depth int    // глубина от корня: "." -> 0; "Games" -> 1; "Games/Warcraft" -> 2
+}
+
+// dirQueue - общая очередь директорий для всех воркеров.
+// Задачи берутся с конца (LIFO), чтобы обход шёл в глубину и очередь оставалась короткой.
+type dirQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	tasks  []dirTask
+	active int // задачи в очереди и в работе; 0 - обход завершён
+
+	rootDone func(r *rootWalk)
+}
+
+func newDirQueue(rootDone func(r *rootWalk)) *dirQueue {
+	q := &dirQueue{rootDone: rootDone}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push добавляет задачи в очередь.
+func (q *dirQueue) push(tasks ...dirTask) {
+	if len(tasks) == 0 {
+		return
+	}
+	q.mu.Lock()
+	for _, t := range tasks {
+		t.root.pending++
+	}
+	q.tasks = append(q.tasks, tasks...)
+	q.active += len(tasks)
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// pop ждёт следующую задачу. Возвращает false, когда задач больше не будет.
+func (q *dirQueue) pop() (dirTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.tasks) == 0 {
+		if q.active == 0 {
+			return dirTask{}, false
+		}
+		q.cond.Wait()
+	}
+	t := q.tasks[len(q.tasks)-1]
+	q.tasks = q.tasks[:len(q.tasks)-1]
+	return t, true
+}
+
+// done отмечает задачу выполненной. Дочерние задачи должны быть добавлены до вызова done.
+func (q *dirQueue) done(t dirTask) {
+	q.mu.Lock()
+	t.root.pending--
+	rootFinished := t.root.pending == 0
+	q.active--
+	finished := q.active == 0
+	q.mu.Unlock()
+
+	if rootFinished {
+		q.rootDone(t.root)
+	}
+	if finished {
+		q.cond.Broadcast()
+	}
+}
+
+// walkRoots обходит все корни пулом воркеров с общей очередью директорий.
+// Медленный корень не задерживает остальные, а большой корень обходится параллельно.
+// Возвращает корни, обойдённые полностью: без отмены сканирования и без таймаута корня.
+func (s *Scanner) walkRoots(ctx context.Context, roots []string, rules ScanRules, st *scanState) (complete []string) {
+	workers := rules.Workers
+	if workers <= 0 {
+		workers = defaultWorkers()
+	}
+
+	var completeMu sync.Mutex
+	q := newDirQueue(func(r *rootWalk) {
+		switch err := r.ctx.Err(); {
+		case errors.Is(err, context.DeadlineExceeded):
+			log.Printf("Обход %s прерван по таймауту (%d с)", r.path, rules.RootTimeoutSec)
+		case err == nil:
+			completeMu.Lock()
+			complete = append(complete, r.path)
+			completeMu.Unlock()
+		}
+		r.cancel()
+		st.rootDone()
+	})
+
+	for _, root := range roots {
+		r := &rootWalk{path: filepath.Clean(root)}
+		if rules.RootTimeoutSec > 0 {
+			r.ctx, r.cancel = context.WithTimeout(ctx, time.Duration(rules.RootTimeoutSec)*time.Second)
+		} else {
+			r.ctx, r.cancel = context.WithCancel(ctx)
+		}
+		q.push(dirTask{root: r, rel: "."})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				t, ok := q.pop()
+				if !ok {
+					return
+				}
+				q.push(s.visit(t, rules, st)...)
+				q.done(t)
+			}
+		}()
+	}
+	wg.Wait() // Ждем завершения всех воркеров
+	return complete
+}
+
+// visit читает директорию задачи и возвращает задачи для её поддиректорий.
+func (s *Scanner) visit(t dirTask, rules ScanRules, st *scanState) []dirTask {
+	r := t.root
+	if r.ctx.Err() != nil {
+		return nil // Сканирование отменено или время корня истекло
+	}
+
+	if t.rel == "." {
+		fsys, err := withContext(r.ctx, func() (fs.FS, error) { return s.fs.Open(r.path) })
+		if err != nil {
+			if r.ctx.Err() == nil {
+				log.Printf("Не удалось открыть %s: %v", r.path, err)
+			}
+			return nil
+		}
+		r.fsys = fsys
+	}
+
+	dir := filepath.Join(r.path, filepath.FromSlash(t.rel))
+	entry, err := withContext(r.ctx, func() (cachedDir, error) { return s.readDirCached(r.fsys, dir, t.rel, rules) })
+	if err != nil {
+		if r.ctx.Err() == nil {
+			// Логируем ошибку, но продолжаем обход
+			log.Printf("Ошибка доступа к пути %s: %v", dir, err)
+		}
+		return nil
+	}
+
+	st.visitDir(r.path)
+	if entry.Target {
+		st.addFound(dir)
+	}
+
+	if t.depth >= rules.MaxDepth {
+		return nil // Не спускаемся глубже MaxDepth
+	}
+	var children []dirTask
+	for _, name := range entry.Subdirs {
+		if rules.isExcluded(name) {
+			continue // Пропускаем исключенные папки
+		}
+		children = append(children, dirTask{root: r, rel: path.Join(t.rel, name), depth: t.depth + 1})
+	}
+	return children
+}
+
+// withContext выполняет fn, но перестаёт ждать результат при отмене ctx.
+// Нужен для сетевых дисков, где чтение директории может зависнуть;
+// без дедлайна fn вызывается напрямую, без лишней горутины.
+func withContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		return fn()
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		ch <- result{v, err}
+	}()
+
+	select {
+	case res := <-ch:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// readDirCached возвращает содержимое директории dir (rel - её путь внутри fsys).
+// Если директория не менялась с прошлого обхода, используется кэш.
+func (s *Scanner) readDirCached(fsys fs.FS, dir, rel string, rules ScanRules) (cachedDir, error) {
+	var modTime int64
+	if s.cache != nil {
+		info, err := fs.Stat(fsys, rel)
+		if err != nil {
+			return cachedDir{}, err
+		}
+		modTime = info.ModTime().UnixNano()
+		if entry, ok := s.cache.lookup(dir, modTime); ok {
+			return entry, nil
+		}
+	}
+
+	entries, err := fs.ReadDir(fsys, rel)
+	if err != nil {
+		return cachedDir{}, err
+	}
+
+	entry := cachedDir{ModTime: modTime, Subdirs: []string{}}
+	for _, e := range entries {
+		if e.IsDir() {
+			entry.Subdirs = append(entry.Subdirs, e.Name())
+		} else if e.Type().IsRegular() && rules.isTarget(e.Name()) {
+			entry.Target = true
+		}
+	}
+
+	if s.cache != nil {
+		s.cache.store(dir, entry)
+	}
+	return entry, nil
+}
@@ -0,0 +1,199 @@
+package paths_scanner
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// delayFS имитирует медленный диск: каждое чтение директории занимает delay.
+// Если block не nil, чтение блокируется до его закрытия (зависший сетевой диск).
+type delayFS struct {
+	fstest.MapFS
+	delay time.Duration
+	block chan struct{}
+}
+
+func (d delayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if d.block != nil {
+		<-d.block
+	}
+	time.Sleep(d.delay)
+	return d.MapFS.ReadDir(name)
+}
+
+// delayFileSystem - набор дисков с разной скоростью.
+type delayFileSystem map[string]delayFS
+
+func (m delayFileSystem) Roots() []string {
+	var roots []string
+	for root := range m {
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+func (m delayFileSystem) Open(root string) (fs.FS, error) {
+	fsys, ok := m[filepath.ToSlash(root)]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return fsys, nil
+}
+
+func (m delayFileSystem) Stat(string) (fs.FileInfo, error) { return nil, fs.ErrNotExist }
+
+func (m delayFileSystem) Writable(string) bool { return true }
+
+// syntheticTree строит дерево с fanout поддиректориями на каждом уровне до глубины depth
+// и установкой игры в последней директории.
+func syntheticTree(fanout, depth int) fstest.MapFS {
+	tree := fstest.MapFS{}
+	var build func(prefix string, level int)
+	build = func(prefix string, level int) {
+		for i := 0; i < fanout; i++ {
+			p := fmt.Sprintf("%sd%d", prefix, i)
+			if level == depth {
+				tree[p+"/file.txt"] = file()
+				continue
+			}
+			build(p+"/", level+1)
+		}
+	}
+	build("", 1)
+	tree[strings.Repeat(fmt.Sprintf("d%d/", fanout-1), depth)+"war3.exe"] = file()
+	return tree
+}
+
+func TestWalkRootsRootTimeout(t *testing.T) {
+	hung := make(chan struct{})
+	defer close(hung)
+
+	disks := delayFileSystem{
+		"/net": {MapFS: fstest.MapFS{"W3/war3.exe": file()}, block: hung},
+		"/c":   {MapFS: fstest.MapFS{"Games/W3/war3.exe": file()}},
+	}
+	s := NewScannerWith(memRegistry{}, disks, nil)
+	rules := DefaultScanRules()
+	rules.RootTimeoutSec = 1
+	s.rules = staticRules(rules)
+
+	start := time.Now()
+	got := s.FindConfigOrExeParallel(context.Background())
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("сканирование заняло %v, таймаут корня не сработал", elapsed)
+	}
+	if want := []string{filepath.FromSlash("/c/Games/W3")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindConfigOrExeParallel() = %q, want %q", got, want)
+	}
+}
+
+func TestWalkRootsParallelMatchesSerial(t *testing.T) {
+	disks := mapFileSystem{
+		"/c": syntheticTree(4, 4),
+		"/d": syntheticTree(3, 3),
+	}
+	for _, workers := range []int{1, 2, 16} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			s := NewScannerWith(memRegistry{}, disks, nil)
+			rules := DefaultScanRules()
+			rules.Workers = workers
+			s.rules = staticRules(rules)
+
+			st := newScanState(noopEmitter{}, 2)
+			s.walkRoots(context.Background(), disks.Roots(), rules, st)
+
+			want := []string{
+				filepath.FromSlash("/c/d3/d3/d3/d3"),
+				filepath.FromSlash("/d/d2/d2/d2"),
+			}
+			if got := st.paths(); !reflect.DeepEqual(got, want) {
+				t.Errorf("walkRoots() = %q, want %q", got, want)
+			}
+			// 1 + 4 + 16 + 64 + 256 и 1 + 3 + 9 + 27 директорий
+			if got := st.snapshot(); got.DirsVisited != 341+40 || got.RootsDone != 2 {
+				t.Errorf("прогресс = %+v, want 381 директорию и 2 корня", got)
+			}
+		})
+	}
+}
+
+// walkPerRoot - прежняя схема обхода: одна горутина на корень, внутри корня обход последовательный.
+// Используется как точка отсчёта в бенчмарке.
+func walkPerRoot(fsys FileSystem, rules ScanRules) []string {
+	var (
+		mu    sync.Mutex
+		found []string
+		wg    sync.WaitGroup
+	)
+	for _, root := range fsys.Roots() {
+		wg.Add(1)
+		go func(root string) {
+			defer wg.Done()
+			rootFS, err := fsys.Open(root)
+			if err != nil {
+				return
+			}
+			_ = fs.WalkDir(rootFS, ".", func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+				if d.IsDir() {
+					if p != "." && (strings.Count(p, "/")+1 > rules.MaxDepth || rules.isExcluded(d.Name())) {
+						return fs.SkipDir
+					}
+					return nil
+				}
+				if rules.isTarget(d.Name()) {
+					mu.Lock()
+					found = append(found, filepath.Join(root, filepath.Dir(p)))
+					mu.Unlock()
+				}
+				return nil
+			})
+		}(root)
+	}
+	wg.Wait()
+	return found
+}
+
+// BenchmarkWalk сравнивает прежний обход (горутина на диск) с пулом воркеров
+// на синтетическом дереве: один большой локальный диск, один медленный сетевой и один маленький.
+func BenchmarkWalk(b *testing.B) {
+	disks := delayFileSystem{
+		"/c":   {MapFS: syntheticTree(6, 4), delay: 20 * time.Microsecond},
+		"/net": {MapFS: syntheticTree(3, 4), delay: 500 * time.Microsecond},
+		"/usb": {MapFS: syntheticTree(2, 2), delay: 50 * time.Microsecond},
+	}
+	rules := DefaultScanRules()
+
+	b.Run("per-root", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if got := walkPerRoot(disks, rules); len(got) != 3 {
+				b.Fatalf("найдено %d путей, want 3", len(got))
+			}
+		}
+	})
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("pool-%d", workers), func(b *testing.B) {
+			s := NewScannerWith(memRegistry{}, disks, nil)
+			r := rules
+			r.Workers = workers
+			for i := 0; i < b.N; i++ {
+				st := newScanState(noopEmitter{}, len(disks))
+				s.walkRoots(context.Background(), disks.Roots(), r, st)
+				if got := len(st.paths()); got != 3 {
+					b.Fatalf("найдено %d путей, want 3", got)
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"lce/backend/modules/data_dir"
 )
 
 // ThemeService отвечает за загрузку/сохранение тем
@@ -17,21 +19,13 @@ func NewThemeService() *ThemeService {
 
 // путь к директории с темами
 func (ts *ThemeService) getThemesDir() (string, error) {
-	themesDir := "themes" // директория в корне рядом с приложением
-
-	_, err := os.ReadDir(themesDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// если папки нет — создаём
-			if err := os.MkdirAll(themesDir, 0755); err != nil {
-				return "", fmt.Errorf("не удалось создать директорию 'themes': %w", err)
-			}
-		} else {
-			return "", fmt.Errorf("не удалось прочитать директорию 'themes': %w", err)
-		}
-	}
+	return ThemesDir()
+}
 
-	return themesDir, nil
+// ThemesDir возвращает директорию с темами, создавая её при необходимости.
+// Расположение выбирает data_dir: рядом с программой или в рабочей папке при разработке.
+func ThemesDir() (string, error) {
+	return data_dir.Dir(data_dir.Themes)
 }
 
 // LoadTheme загружает и разрешает все переменные
@@ -0,0 +1,146 @@
+package windows
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+
+	"lce/backend/modules/app_settings"
+)
+
+// geometrySaveDelay - пауза после последнего перемещения или изменения размера перед сохранением,
+// чтобы перетаскивание окна не записывало settings.json на каждый шаг.
+const geometrySaveDelay = 500 * time.Millisecond
+
+// Geometry сохраняет положение, размер и развёрнутость окна в настройках и восстанавливает их.
+type Geometry struct {
+	app    *application.App
+	window *application.WebviewWindow
+	name   string
+	store  *app_settings.Store
+
+	mu        sync.Mutex
+	timer     *time.Timer
+	restoring bool // события от Restore не сохраняются
+}
+
+// TrackGeometry начинает сохранять состояние окна под именем name (см. app_settings.MainWindow).
+// Восстанавливает состояние вызов Restore: после запуска приложения, когда известны мониторы.
+func TrackGeometry(app *application.App, window *application.WebviewWindow, name string, store *app_settings.Store) *Geometry {
+	g := &Geometry{app: app, window: window, name: name, store: store}
+
+	for _, event := range []events.WindowEventType{
+		events.Common.WindowDidMove,
+		events.Common.WindowDidResize,
+		events.Common.WindowMaximise,
+		events.Common.WindowUnMaximise,
+		events.Common.WindowRestore,
+	} {
+		window.OnWindowEvent(event, func(*application.WindowEvent) { g.schedule() })
+	}
+	// Закрытие не ждёт паузы: последнее положение сохраняется сразу
+	window.OnWindowEvent(events.Common.WindowClosing, func(*application.WindowEvent) { g.Flush() })
+	return g
+}
+
+// Restore применяет сохранённое состояние окна. Если окно оказалось бы за пределами
+// подключённых мониторов, оно переносится на видимый монитор.
+// Возвращает false, если сохранённого состояния нет.
+func (g *Geometry) Restore() bool {
+	state, ok := g.store.Get().Window(g.name)
+	if !ok {
+		return false
+	}
+	fitted := state.FitTo(g.screens())
+
+	g.mu.Lock()
+	g.restoring = true
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		g.restoring = false
+		g.mu.Unlock()
+	}()
+
+	g.window.SetBounds(application.Rect{X: fitted.X, Y: fitted.Y, Width: fitted.Width, Height: fitted.Height})
+	if fitted.Maximized {
+		g.window.Maximise()
+	}
+	if fitted != state {
+		log.Printf("Окно %s перенесено на видимый монитор %s", g.name, fitted.Monitor)
+		g.save(fitted)
+	}
+	return true
+}
+
+// Flush сохраняет текущее состояние окна, не дожидаясь паузы.
+func (g *Geometry) Flush() {
+	g.mu.Lock()
+	if g.timer != nil {
+		g.timer.Stop()
+		g.timer = nil
+	}
+	g.mu.Unlock()
+	g.capture()
+}
+
+// schedule откладывает сохранение до окончания серии перемещений.
+func (g *Geometry) schedule() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.restoring {
+		return
+	}
+	if g.timer != nil {
+		g.timer.Stop()
+	}
+	g.timer = time.AfterFunc(geometrySaveDelay, g.capture)
+}
+
+// capture читает состояние окна и сохраняет его.
+func (g *Geometry) capture() {
+	if !g.window.IsVisible() || g.window.IsMinimised() || g.window.IsFullscreen() {
+		// Свёрнутое окно и полноэкранный режим не запоминаем: восстанавливать их не нужно
+		return
+	}
+
+	state, _ := g.store.Get().Window(g.name)
+	state.Maximized = g.window.IsMaximised()
+	if !state.Maximized {
+		// У развёрнутого окна сохраняем прежние границы, чтобы было куда вернуть его
+		bounds := g.window.Bounds()
+		state.X, state.Y, state.Width, state.Height = bounds.X, bounds.Y, bounds.Width, bounds.Height
+	}
+	if screen, err := g.window.GetScreen(); err == nil && screen != nil {
+		state.Monitor = screen.ID
+	}
+	if state.Width <= 0 || state.Height <= 0 {
+		return
+	}
+	g.save(state)
+}
+
+func (g *Geometry) save(state app_settings.WindowState) {
+	if err := g.store.SaveWindowState(g.name, state); err != nil {
+		log.Printf("Не удалось сохранить положение окна %s: %v", g.name, err)
+	}
+}
+
+// screens возвращает рабочие области подключённых мониторов.
+func (g *Geometry) screens() []app_settings.ScreenArea {
+	var areas []app_settings.ScreenArea
+	for _, screen := range g.app.Screen.GetAll() {
+		areas = append(areas, app_settings.ScreenArea{
+			ID:      screen.ID,
+			X:       screen.WorkArea.X,
+			Y:       screen.WorkArea.Y,
+			Width:   screen.WorkArea.Width,
+			Height:  screen.WorkArea.Height,
+			Primary: screen.IsPrimary,
+		})
+	}
+	return areas
+}
@@ -3,34 +3,47 @@ package windows
 import (
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/events"
+
+	"lce/backend/modules/app_settings"
 )
 
 type SettingsWindow struct {
-	app    *application.App
-	parent *application.WebviewWindow
-	child  *application.WebviewWindow
+	app      *application.App
+	parent   *application.WebviewWindow
+	child    *application.WebviewWindow
+	geometry *Geometry
 }
 
-// NewSettingsWindow создаёт объект окна настроек и сразу создаёт скрытое дочернее окно
-func NewSettingsWindow(app *application.App, parent *application.WebviewWindow) *SettingsWindow {
+// NewSettingsWindow создаёт объект окна настроек и сразу создаёт скрытое дочернее окно.
+// Положение и размер окна сохраняются в настройках store.
+func NewSettingsWindow(app *application.App, parent *application.WebviewWindow, store *app_settings.Store) *SettingsWindow {
 	sw := &SettingsWindow{
 		app:    app,
 		parent: parent,
 	}
 
+	width, height := 800, 600
+	if state, ok := store.Get().Window(app_settings.SettingsWindow); ok {
+		width, height = state.Width, state.Height
+	}
+
 	// Создаём окно один раз, сразу скрытое
 	sw.child = sw.app.Window.NewWithOptions(application.WebviewWindowOptions{
 		Title:     "Settings",
-		Width:     800,
-		Height:    600,
+		Width:     width,
+		Height:    height,
 		Frameless: true,
 		URL:       "/#/settings", // Хеш-навигация для отдельного контента
 		Hidden:    true,
 	})
+	sw.geometry = TrackGeometry(app, sw.child, app_settings.SettingsWindow, store)
 
 	// Закрытие дочернего окна при закрытии родителя
 	sw.parent.OnWindowEvent(events.Common.WindowClosing, func(e *application.WindowEvent) {
 		if sw.child != nil {
+			if sw.child.IsVisible() {
+				sw.geometry.Flush()
+			}
 			sw.child.Close()
 		}
 	})
@@ -49,11 +62,15 @@ func (sw *SettingsWindow) Open() {
 		}
 		return
 	}
-	sw.child.Center()
+	// Окно открывается там, где его оставили; в первый раз - по центру
+	if !sw.geometry.Restore() {
+		sw.child.Center()
+	}
 	sw.child.Show()
 }
 
-// Close скрывает окно
+// Close скрывает окно, запомнив его положение
 func (sw *SettingsWindow) Close() {
+	sw.geometry.Flush()
 	sw.child.Hide()
 }
@@ -4,7 +4,7 @@
 
 /**
  * AppSettings - это структура, которая будет привязана к фронтенду Wails.
- * Она содержит текущие настройки и мьютекс для потокобезопасного доступа.
+ * Она открывает фронтенду хранилище настроек Store, общее для всех сервисов.
  * @module
  */
 
@@ -12,12 +12,88 @@
 // @ts-ignore: Unused imports
 import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as data_dir$0 from "../data_dir/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as paths_scanner$0 from "../paths_scanner/models.js";
+
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
 import * as $models from "./models.js";
 
 /**
- * GetOption возвращает значение определенной опции по ключу.
+ * AddInstall добавляет установку для папки path. Если такая уже есть, возвращается существующая.
+ * Первая добавленная установка сразу становится активной.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} path
+ * @param {string} label
+ * @returns {$CancellablePromise<$models.Install>}
+ */
+export function AddInstall(path, label) {
+    return $Call.ByID(757733814, path, label).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * ExportSettings сохраняет настройки приложения (язык, тему, установки, правила сканирования и т.д.)
+ * в файл path. Положение окон не экспортируется.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} path
+ * @returns {$CancellablePromise<void>}
+ */
+export function ExportSettings(path) {
+    return $Call.ByID(2090465223, path);
+}
+
+/**
+ * Fields возвращает описание всех настроек.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<$models.Field[]>}
+ */
+export function Fields() {
+    return $Call.ByID(4270624449).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType2($result);
+    }));
+}
+
+/**
+ * GetActiveInstall возвращает активную установку или ошибку, если она не выбрана.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<$models.Install>}
+ */
+export function GetActiveInstall() {
+    return $Call.ByID(3503798329).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * GetDataLayout возвращает, где приложение хранит файлы и включён ли переносной режим.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<data_dir$0.Layout>}
+ */
+export function GetDataLayout() {
+    return $Call.ByID(1264939118).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * GetInstalls возвращает список известных установок.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<$models.Install[]>}
+ */
+export function GetInstalls() {
+    return $Call.ByID(1960792064).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType4($result);
+    }));
+}
+
+/**
+ * GetOption возвращает значение определенной опции по ключу или nil для неизвестного ключа.
  * Эта функция привязана к фронтенду Wails.
  * @param {string} key
  * @returns {$CancellablePromise<any>}
@@ -26,6 +102,17 @@ export function GetOption(key) {
     return $Call.ByID(2101468265, key);
 }
 
+/**
+ * GetScanRules возвращает текущие правила сканирования.
+ * Используется сканером путей и привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<paths_scanner$0.ScanRules>}
+ */
+export function GetScanRules() {
+    return $Call.ByID(3912292852).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType5($result);
+    }));
+}
+
 /**
  * GetSettings возвращает текущие настройки приложения.
  * Эта функция привязана к фронтенду Wails.
@@ -33,21 +120,174 @@ export function GetOption(key) {
  */
 export function GetSettings() {
     return $Call.ByID(537428349).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType6($result);
+    }));
+}
+
+/**
+ * ImportSettings заменяет настройки приложения настройками из файла экспорта.
+ * Перед заменой текущие настройки сохраняются в папку резервных копий; путь к копии возвращается.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} path
+ * @returns {$CancellablePromise<string>}
+ */
+export function ImportSettings(path) {
+    return $Call.ByID(2460044388, path);
+}
+
+/**
+ * KnownInstallPaths возвращает пути всех известных установок (для фоновой проверки в paths_scanner).
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function KnownInstallPaths() {
+    return $Call.ByID(2773277046).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType7($result);
+    }));
+}
+
+/**
+ * PreviewImport проверяет файл экспорта и возвращает список настроек, которые изменятся при импорте.
+ * Текущие настройки не меняются.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} path
+ * @returns {$CancellablePromise<$models.ImportPreview>}
+ */
+export function PreviewImport(path) {
+    return $Call.ByID(3386614029, path).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType8($result);
+    }));
+}
+
+/**
+ * RelabelInstall меняет отображаемое имя установки.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} id
+ * @param {string} label
+ * @returns {$CancellablePromise<$models.Install>}
+ */
+export function RelabelInstall(id, label) {
+    return $Call.ByID(1645141080, id, label).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * ReloadSettings перечитывает settings.json (например, после правки вручную).
+ * Если настройки на диске отличаются от текущих, они применяются и фронтенд уведомляется.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<$models.Settings>}
+ */
+export function ReloadSettings() {
+    return $Call.ByID(3733100804).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType6($result);
+    }));
+}
+
+/**
+ * RemoveInstall удаляет установку. Если она была активной, активной становится
+ * последняя использованная из оставшихся.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} id
+ * @returns {$CancellablePromise<void>}
+ */
+export function RemoveInstall(id) {
+    return $Call.ByID(3223364959, id);
+}
+
+/**
+ * RemoveInstallPath удаляет установку с папкой path, как RemoveInstall.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} path
+ * @returns {$CancellablePromise<void>}
+ */
+export function RemoveInstallPath(path) {
+    return $Call.ByID(3776431864, path);
+}
+
+/**
+ * ResetSettings возвращает настройки по умолчанию. Положение окон сохраняется,
+ * текущие настройки перед сбросом копируются в папку резервных копий; путь к копии возвращается.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<string>}
+ */
+export function ResetSettings() {
+    return $Call.ByID(2828964798);
+}
+
+/**
+ * SetInstallProfile запоминает активный профиль настроек установки.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} id
+ * @param {string} profile
+ * @returns {$CancellablePromise<$models.Install>}
+ */
+export function SetInstallProfile(id, profile) {
+    return $Call.ByID(1834399128, id, profile).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * SetInstallWatching включает или выключает наблюдение за config.lod.ini установки.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} id
+ * @param {boolean} enabled
+ * @returns {$CancellablePromise<$models.Install>}
+ */
+export function SetInstallWatching(id, enabled) {
+    return $Call.ByID(3356534322, id, enabled).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * SwitchInstall делает установку активной. config_editor и config_watcher
+ * переключаются на неё по событию EventInstallSwitched.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} id
+ * @returns {$CancellablePromise<$models.Install>}
+ */
+export function SwitchInstall(id) {
+    return $Call.ByID(1104470339, id).then(/** @type {($result: any) => any} */(($result) => {
         return $$createType0($result);
     }));
 }
 
 /**
  * UpdateSettings обновляет настройки приложения на основе предоставленной карты.
+ * Значения проверяются по реестру settingsFields; если хотя бы одно некорректно,
+ * ничего не сохраняется и возвращается *ValidationError с ошибками по полям.
  * Эта функция привязана к фронтенду Wails.
  * @param {{ [_: string]: any }} newSettings
  * @returns {$CancellablePromise<$models.Settings>}
  */
 export function UpdateSettings(newSettings) {
     return $Call.ByID(649780664, newSettings).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType0($result);
+        return $$createType6($result);
+    }));
+}
+
+/**
+ * ValidateSettings проверяет значения, не сохраняя их, и возвращает ошибки по полям.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {{ [_: string]: any }} values
+ * @returns {$CancellablePromise<$models.FieldError[]>}
+ */
+export function ValidateSettings(values) {
+    return $Call.ByID(1865874455, values).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType10($result);
     }));
 }
 
 // Private type creation functions
-const $$createType0 = $models.Settings.createFrom;
+const $$createType0 = $models.Install.createFrom;
+const $$createType1 = $models.Field.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = data_dir$0.Layout.createFrom;
+const $$createType4 = $Create.Array($$createType0);
+const $$createType5 = paths_scanner$0.ScanRules.createFrom;
+const $$createType6 = $models.Settings.createFrom;
+const $$createType7 = $Create.Array($Create.Any);
+const $$createType8 = $models.ImportPreview.createFrom;
+const $$createType9 = $models.FieldError.createFrom;
+const $$createType10 = $Create.Array($$createType9);
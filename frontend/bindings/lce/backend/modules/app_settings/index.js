@@ -8,5 +8,12 @@ export {
 };
 
 export {
-    Settings
+    Field,
+    FieldError,
+    FieldType,
+    ImportPreview,
+    Install,
+    SettingChange,
+    Settings,
+    WindowState
 } from "./models.js";
@@ -6,6 +6,331 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as config_watcher$0 from "../config_watcher/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as paths_scanner$0 from "../paths_scanner/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../../../../time/models.js";
+
+/**
+ * Field - описание одной настройки: имя в settings.json, тип, значение по умолчанию и правила изменения.
+ * По реестру settingsFields работают UpdateSettings, GetOption, проверка значений и генерация типов TypeScript.
+ */
+export class Field {
+    /**
+     * Creates a new Field instance.
+     * @param {Partial<Field>} [$$source = {}] - The source object to create the Field.
+     */
+    constructor($$source = {}) {
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("type" in $$source)) {
+            /**
+             * @member
+             * @type {FieldType}
+             */
+            this["type"] = FieldType.$zero;
+        }
+        if (!("default" in $$source)) {
+            /**
+             * @member
+             * @type {any}
+             */
+            this["default"] = null;
+        }
+        if (!("requires_restart" in $$source)) {
+            /**
+             * новое значение применяется после перезапуска
+             * @member
+             * @type {boolean}
+             */
+            this["requires_restart"] = false;
+        }
+        if (!("read_only" in $$source)) {
+            /**
+             * меняется только отдельными методами, не через UpdateSettings
+             * @member
+             * @type {boolean}
+             */
+            this["read_only"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Field instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Field}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Field(/** @type {Partial<Field>} */($$parsedSource));
+    }
+}
+
+/**
+ * FieldError - ошибка в значении одной настройки.
+ */
+export class FieldError {
+    /**
+     * Creates a new FieldError instance.
+     * @param {Partial<FieldError>} [$$source = {}] - The source object to create the FieldError.
+     */
+    constructor($$source = {}) {
+        if (!("field" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["field"] = "";
+        }
+        if (!("message" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["message"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new FieldError instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {FieldError}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new FieldError(/** @type {Partial<FieldError>} */($$parsedSource));
+    }
+}
+
+/**
+ * FieldType - тип значения настройки в JSON, как его видит фронтенд.
+ * @readonly
+ * @enum {string}
+ */
+export const FieldType = {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero: "",
+
+    FieldNumber: "number",
+    FieldString: "string",
+    FieldBool: "bool",
+    FieldStringList: "string_list",
+    FieldObject: "object",
+};
+
+/**
+ * ImportPreview - что изменится при импорте файла.
+ */
+export class ImportPreview {
+    /**
+     * Creates a new ImportPreview instance.
+     * @param {Partial<ImportPreview>} [$$source = {}] - The source object to create the ImportPreview.
+     */
+    constructor($$source = {}) {
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("version" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["version"] = 0;
+        }
+        if (!("exported_at" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time}
+             */
+            this["exported_at"] = null;
+        }
+        if (!("changes" in $$source)) {
+            /**
+             * @member
+             * @type {SettingChange[]}
+             */
+            this["changes"] = [];
+        }
+        if (!("warnings" in $$source)) {
+            /**
+             * например, папки установок, которых нет на этом компьютере
+             * @member
+             * @type {string[]}
+             */
+            this["warnings"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportPreview instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ImportPreview}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType1;
+        const $$createField4_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("changes" in $$parsedSource) {
+            $$parsedSource["changes"] = $$createField3_0($$parsedSource["changes"]);
+        }
+        if ("warnings" in $$parsedSource) {
+            $$parsedSource["warnings"] = $$createField4_0($$parsedSource["warnings"]);
+        }
+        return new ImportPreview(/** @type {Partial<ImportPreview>} */($$parsedSource));
+    }
+}
+
+/**
+ * Install - установка игры, известная приложению, со своим состоянием.
+ */
+export class Install {
+    /**
+     * Creates a new Install instance.
+     * @param {Partial<Install>} [$$source = {}] - The source object to create the Install.
+     */
+    constructor($$source = {}) {
+        if (!("id" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["id"] = "";
+        }
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("label" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["label"] = "";
+        }
+        if (!("game_version" in $$source)) {
+            /**
+             * версия war3.exe на момент добавления или переключения
+             * @member
+             * @type {string}
+             */
+            this["game_version"] = "";
+        }
+        if (!("last_used" in $$source)) {
+            /**
+             * когда установка последний раз становилась активной
+             * @member
+             * @type {time$0.Time}
+             */
+            this["last_used"] = null;
+        }
+        if (!("active_profile" in $$source)) {
+            /**
+             * выбранный профиль настроек для этой установки
+             * @member
+             * @type {string}
+             */
+            this["active_profile"] = "";
+        }
+        if (!("watch_enabled" in $$source)) {
+            /**
+             * следить ли за внешними изменениями config.lod.ini
+             * @member
+             * @type {boolean}
+             */
+            this["watch_enabled"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Install instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Install}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Install(/** @type {Partial<Install>} */($$parsedSource));
+    }
+}
+
+/**
+ * SettingChange - отличие импортируемой настройки от текущей.
+ */
+export class SettingChange {
+    /**
+     * Creates a new SettingChange instance.
+     * @param {Partial<SettingChange>} [$$source = {}] - The source object to create the SettingChange.
+     */
+    constructor($$source = {}) {
+        if (!("field" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["field"] = "";
+        }
+        if (!("current" in $$source)) {
+            /**
+             * @member
+             * @type {any}
+             */
+            this["current"] = null;
+        }
+        if (!("imported" in $$source)) {
+            /**
+             * @member
+             * @type {any}
+             */
+            this["imported"] = null;
+        }
+        if (!("requires_restart" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["requires_restart"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SettingChange instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {SettingChange}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SettingChange(/** @type {Partial<SettingChange>} */($$parsedSource));
+    }
+}
+
 /**
  * Settings - структура для хранения настроек приложения
  */
@@ -15,6 +340,14 @@ export class Settings {
      * @param {Partial<Settings>} [$$source = {}] - The source object to create the Settings.
      */
     constructor($$source = {}) {
+        if (!("version" in $$source)) {
+            /**
+             * Версия формата файла, см. CurrentSettingsVersion
+             * @member
+             * @type {number}
+             */
+            this["version"] = 0;
+        }
         if (!("width" in $$source)) {
             /**
              * @member
@@ -67,6 +400,54 @@ export class Settings {
              */
             this["theme"] = "";
         }
+        if (!("scan_rules" in $$source)) {
+            /**
+             * Правила поиска папок с игрой
+             * @member
+             * @type {paths_scanner$0.ScanRules}
+             */
+            this["scan_rules"] = (new paths_scanner$0.ScanRules());
+        }
+        if (!("installs" in $$source)) {
+            /**
+             * Известные установки игры
+             * @member
+             * @type {Install[]}
+             */
+            this["installs"] = [];
+        }
+        if (!("active_install_id" in $$source)) {
+            /**
+             * Активная установка; GamePath и AllPaths повторяют Installs
+             * @member
+             * @type {string}
+             */
+            this["active_install_id"] = "";
+        }
+        if (!("watch_mode" in $$source)) {
+            /**
+             * auto, native или poll
+             * @member
+             * @type {config_watcher$0.Mode}
+             */
+            this["watch_mode"] = config_watcher$0.Mode.$zero;
+        }
+        if (!("watch_poll_interval_ms" in $$source)) {
+            /**
+             * интервал опроса файлов в режиме poll
+             * @member
+             * @type {number}
+             */
+            this["watch_poll_interval_ms"] = 0;
+        }
+        if (!("windows" in $$source)) {
+            /**
+             * положение и размер окон по именам (MainWindow, SettingsWindow)
+             * @member
+             * @type {{ [_: string]: WindowState }}
+             */
+            this["windows"] = {};
+        }
 
         Object.assign(this, $$source);
     }
@@ -77,14 +458,100 @@ export class Settings {
      * @returns {Settings}
      */
     static createFrom($$source = {}) {
-        const $$createField5_0 = $$createType0;
+        const $$createField6_0 = $$createType2;
+        const $$createField8_0 = $$createType3;
+        const $$createField9_0 = $$createType5;
+        const $$createField13_0 = $$createType7;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("all_paths" in $$parsedSource) {
-            $$parsedSource["all_paths"] = $$createField5_0($$parsedSource["all_paths"]);
+            $$parsedSource["all_paths"] = $$createField6_0($$parsedSource["all_paths"]);
+        }
+        if ("scan_rules" in $$parsedSource) {
+            $$parsedSource["scan_rules"] = $$createField8_0($$parsedSource["scan_rules"]);
+        }
+        if ("installs" in $$parsedSource) {
+            $$parsedSource["installs"] = $$createField9_0($$parsedSource["installs"]);
+        }
+        if ("windows" in $$parsedSource) {
+            $$parsedSource["windows"] = $$createField13_0($$parsedSource["windows"]);
         }
         return new Settings(/** @type {Partial<Settings>} */($$parsedSource));
     }
 }
 
+/**
+ * WindowState - сохранённые положение и размер окна (в независимых от DPI единицах).
+ */
+export class WindowState {
+    /**
+     * Creates a new WindowState instance.
+     * @param {Partial<WindowState>} [$$source = {}] - The source object to create the WindowState.
+     */
+    constructor($$source = {}) {
+        if (!("x" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["x"] = 0;
+        }
+        if (!("y" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["y"] = 0;
+        }
+        if (!("width" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["width"] = 0;
+        }
+        if (!("height" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["height"] = 0;
+        }
+        if (!("maximized" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["maximized"] = false;
+        }
+        if (!("monitor" in $$source)) {
+            /**
+             * идентификатор монитора, на котором было окно
+             * @member
+             * @type {string}
+             */
+            this["monitor"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WindowState instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {WindowState}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WindowState(/** @type {Partial<WindowState>} */($$parsedSource));
+    }
+}
+
 // Private type creation functions
-const $$createType0 = $Create.Array($Create.Any);
+const $$createType0 = SettingChange.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = $Create.Array($Create.Any);
+const $$createType3 = paths_scanner$0.ScanRules.createFrom;
+const $$createType4 = Install.createFrom;
+const $$createType5 = $Create.Array($$createType4);
+const $$createType6 = WindowState.createFrom;
+const $$createType7 = $Create.Map($Create.Any, $$createType6);
@@ -73,6 +73,18 @@ export function SetConfigValue(section, option, value) {
     return $Call.ByID(3299114961, section, option, value);
 }
 
+/**
+ * SwitchInstall переключает редактор на config.lod.ini другой установки.
+ * Если конфиг новой установки не читается, редактор остаётся пустым,
+ * чтобы изменения не записались в конфиг предыдущей установки.
+ * Пустой gamePath отвязывает редактор от конфига: активной установки больше нет.
+ * @param {string} gamePath
+ * @returns {$CancellablePromise<void>}
+ */
+export function SwitchInstall(gamePath) {
+    return $Call.ByID(1173722717, gamePath);
+}
+
 // Private type creation functions
 const $$createType0 = $Create.Map($Create.Any, $Create.Any);
 const $$createType1 = $Create.Map($Create.Any, $$createType0);
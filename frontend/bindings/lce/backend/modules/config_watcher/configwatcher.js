@@ -2,12 +2,24 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+/**
+ * ConfigWatcher - привязанный к фронтенду сервис наблюдения за config.lod.ini активной установки.
+ * Само наблюдение выполняет Hub (цель TargetGameConfig), через него же следят за темами, переводами и настройками.
+ * @module
+ */
+
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
 import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
 /**
- * StartWatching — вызывается из фронтенда
+ * StartWatching начинает наблюдение за файлом path. Если за этим файлом уже следят
+ * с тем же интервалом, ничего не происходит; иначе предыдущее наблюдение останавливается.
+ * Вызывается из фронтенда.
  * @param {string} path
  * @param {number} debounceMs
  * @returns {$CancellablePromise<void>}
@@ -17,9 +29,47 @@ export function StartWatching(path, debounceMs) {
 }
 
 /**
- * StopWatching — останавливает наблюдение
+ * Status возвращает состояние наблюдения за конфигом.
+ * Вызывается из фронтенда.
+ * @returns {$CancellablePromise<$models.Status>}
+ */
+export function Status() {
+    return $Call.ByID(1014778082).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * Statuses возвращает состояние всех целей наблюдения (конфиг, темы, переводы, настройки).
+ * Вызывается из фронтенда.
+ * @returns {$CancellablePromise<$models.Status[]>}
+ */
+export function Statuses() {
+    return $Call.ByID(362334018).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType1($result);
+    }));
+}
+
+/**
+ * StopWatching останавливает наблюдение и дожидается завершения горутины вотчера.
+ * Повторный вызов ничего не делает. Вызывается из фронтенда.
  * @returns {$CancellablePromise<void>}
  */
 export function StopWatching() {
     return $Call.ByID(3766162357);
 }
+
+/**
+ * SwitchInstall переключает наблюдение на конфиг другой установки.
+ * Если watch == false, наблюдение останавливается.
+ * @param {string} path
+ * @param {boolean} watch
+ * @returns {$CancellablePromise<void>}
+ */
+export function SwitchInstall(path, watch) {
+    return $Call.ByID(2264815927, path, watch);
+}
+
+// Private type creation functions
+const $$createType0 = $models.Status.createFrom;
+const $$createType1 = $Create.Array($$createType0);
@@ -6,3 +6,9 @@ import * as ConfigWatcher from "./configwatcher.js";
 export {
     ConfigWatcher
 };
+
+export {
+    FileChange,
+    Mode,
+    Status
+} from "./models.js";
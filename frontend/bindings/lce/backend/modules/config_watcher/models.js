@@ -0,0 +1,211 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../../../../time/models.js";
+
+/**
+ * FileChange - данные событий об изменении файлов (EventConfigChanged и других).
+ */
+export class FileChange {
+    /**
+     * Creates a new FileChange instance.
+     * @param {Partial<FileChange>} [$$source = {}] - The source object to create the FileChange.
+     */
+    constructor($$source = {}) {
+        if (!("target" in $$source)) {
+            /**
+             * имя цели наблюдения
+             * @member
+             * @type {string}
+             */
+            this["target"] = "";
+        }
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("hash" in $$source)) {
+            /**
+             * SHA-256 нового содержимого; пусто, если файл удалён
+             * @member
+             * @type {string}
+             */
+            this["hash"] = "";
+        }
+        if (!("removed" in $$source)) {
+            /**
+             * файл удалён из наблюдаемой папки
+             * @member
+             * @type {boolean}
+             */
+            this["removed"] = false;
+        }
+        if (!("internal" in $$source)) {
+            /**
+             * изменение записано самим приложением
+             * @member
+             * @type {boolean}
+             */
+            this["internal"] = false;
+        }
+        if (!("generation" in $$source)) {
+            /**
+             * номер записи приложения; 0 для внешних изменений
+             * @member
+             * @type {number}
+             */
+            this["generation"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new FileChange instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {FileChange}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new FileChange(/** @type {Partial<FileChange>} */($$parsedSource));
+    }
+}
+
+/**
+ * Mode - способ отслеживания изменений.
+ * @readonly
+ * @enum {string}
+ */
+export const Mode = {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero: "",
+
+    /**
+     * уведомления ОС, а там, где они ненадёжны, - опрос
+     */
+    ModeAuto: "auto",
+
+    /**
+     * только уведомления ОС
+     */
+    ModeNative: "native",
+
+    /**
+     * только опрос
+     */
+    ModePoll: "poll",
+};
+
+/**
+ * Status - состояние наблюдения за целью для фронтенда.
+ */
+export class Status {
+    /**
+     * Creates a new Status instance.
+     * @param {Partial<Status>} [$$source = {}] - The source object to create the Status.
+     */
+    constructor($$source = {}) {
+        if (!("name" in $$source)) {
+            /**
+             * имя цели
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("watching" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["watching"] = false;
+        }
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("since" in $$source)) {
+            /**
+             * когда началось наблюдение
+             * @member
+             * @type {time$0.Time}
+             */
+            this["since"] = null;
+        }
+        if (!("last_event" in $$source)) {
+            /**
+             * когда последний раз сообщалось об изменении
+             * @member
+             * @type {time$0.Time}
+             */
+            this["last_event"] = null;
+        }
+        if (!("last_change" in $$source)) {
+            /**
+             * последнее изменение; nil, если изменений не было
+             * @member
+             * @type {FileChange | null}
+             */
+            this["last_change"] = null;
+        }
+        if (!("error" in $$source)) {
+            /**
+             * последняя ошибка наблюдения; пусто, если всё в порядке
+             * @member
+             * @type {string}
+             */
+            this["error"] = "";
+        }
+        if (!("mode" in $$source)) {
+            /**
+             * фактический способ: ModeNative или ModePoll
+             * @member
+             * @type {Mode}
+             */
+            this["mode"] = Mode.$zero;
+        }
+        if (!("mode_reason" in $$source)) {
+            /**
+             * почему используется опрос
+             * @member
+             * @type {string}
+             */
+            this["mode_reason"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Status instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Status}
+     */
+    static createFrom($$source = {}) {
+        const $$createField5_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("last_change" in $$parsedSource) {
+            $$parsedSource["last_change"] = $$createField5_0($$parsedSource["last_change"]);
+        }
+        return new Status(/** @type {Partial<Status>} */($$parsedSource));
+    }
+}
+
+// Private type creation functions
+const $$createType0 = FileChange.createFrom;
+const $$createType1 = $Create.Nullable($$createType0);
@@ -0,0 +1,62 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Layout - выбранное расположение файлов приложения.
+ */
+export class Layout {
+    /**
+     * Creates a new Layout instance.
+     * @param {Partial<Layout>} [$$source = {}] - The source object to create the Layout.
+     */
+    constructor($$source = {}) {
+        if (!("portable" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["portable"] = false;
+        }
+        if (!("reason" in $$source)) {
+            /**
+             * почему выбран этот режим
+             * @member
+             * @type {string}
+             */
+            this["reason"] = "";
+        }
+        if (!("data" in $$source)) {
+            /**
+             * корень данных: настройки, резервные копии, профили, журналы, переводы
+             * @member
+             * @type {string}
+             */
+            this["data"] = "";
+        }
+        if (!("assets" in $$source)) {
+            /**
+             * папка, в которой лежат themes
+             * @member
+             * @type {string}
+             */
+            this["assets"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Layout instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Layout}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Layout(/** @type {Partial<Layout>} */($$parsedSource));
+    }
+}
@@ -11,6 +11,42 @@
 // @ts-ignore: Unused imports
 import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * ClearCache забывает собранные переводы, чтобы следующий запрос перечитал файлы.
+ * Вызывается при изменении файлов переводов.
+ * @returns {$CancellablePromise<void>}
+ */
+export function ClearCache() {
+    return $Call.ByID(4005344626);
+}
+
+/**
+ * DetectSystemLanguage возвращает код доступного перевода, лучше всего подходящего
+ * к языку операционной системы (LANG/LC_* в Linux, языковые параметры пользователя в Windows).
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<string>}
+ */
+export function DetectSystemLanguage() {
+    return $Call.ByID(2547010135);
+}
+
+/**
+ * GetCompletenessReports возвращает отчёты о полноте переводов (встроенных вместе с файлами
+ * пользователя) для языков codes, а без кодов - для всех языков, кроме английского.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string[]} codes
+ * @returns {$CancellablePromise<$models.LocaleReport[]>}
+ */
+export function GetCompletenessReports(codes) {
+    return $Call.ByID(804424504, codes).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType1($result);
+    }));
+}
+
 /**
  * GetCurrentLanguage возвращает текущий выбранный язык из настроек приложения.
  * @returns {$CancellablePromise<string>}
@@ -20,25 +56,29 @@ export function GetCurrentLanguage() {
 }
 
 /**
- * GetLanguages возвращает список доступных языков.
- * Он сканирует директорию 'locales', читает JSON-файлы
- * и извлекает код языка (имя файла) и имя языка (из поля "lang_name" в JSON).
+ * GetLanguages возвращает список доступных языков: встроенные и добавленные пользователем.
+ * Для каждого языка возвращаются код (имя файла), имя (поле "lang_name" в JSON)
+ * и источник: "builtin", "user" или "builtin+user".
  * @returns {$CancellablePromise<{ [_: string]: string }[]>}
  */
 export function GetLanguages() {
     return $Call.ByID(379549308).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType1($result);
+        return $$createType3($result);
     }));
 }
 
 /**
- * GetTranslations возвращает переводы для указанного кода языка.
+ * GetTranslations возвращает переводы для указанного кода языка по полным ключам
+ * ("hotkeys.tooltips.click_for_bind") и, где это однозначно, по коротким ("click_for_bind").
+ * Ключи из файла пользователя заменяют встроенные, поэтому в своём файле достаточно
+ * перечислить только изменённые строки. Недостающие строки берутся из запасных языков
+ * (см. GetTranslationsWithFallback).
  * @param {string} langCode
  * @returns {$CancellablePromise<{ [_: string]: string }>}
  */
 export function GetTranslations(langCode) {
     return $Call.ByID(1103998873, langCode).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType0($result);
+        return $$createType2($result);
     }));
 }
 
@@ -48,12 +88,49 @@ export function GetTranslations(langCode) {
  */
 export function GetTranslationsCurrent() {
     return $Call.ByID(2358537334).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType0($result);
+        return $$createType2($result);
+    }));
+}
+
+/**
+ * GetTranslationsWithFallback возвращает переводы языка, дополненные по цепочке запасных языков
+ * (например, "es-MX" -> "es" -> "en"), и список ключей, взятых из запасных.
+ * Пустая строка считается непереведённой и тоже заменяется запасной.
+ * Собранные переводы запоминаются до ClearCache; возвращаемые карты изменять нельзя.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} langCode
+ * @returns {$CancellablePromise<$models.Translations>}
+ */
+export function GetTranslationsWithFallback(langCode) {
+    return $Call.ByID(719437267, langCode).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType4($result);
     }));
 }
 
+/**
+ * Publish задаёт, куда отправлять события о перезагрузке переводов.
+ * @param {$models.EventEmitter} events
+ * @returns {$CancellablePromise<void>}
+ */
+export function Publish(events) {
+    return $Call.ByID(3190128526, events);
+}
+
+/**
+ * ReloadLocale проверяет изменённый файл перевода path и отправляет фронтенду EventLocaleReloaded
+ * с новыми переводами текущего языка, если файл входит в его цепочку запасных языков.
+ * Вызывается вотчером в режиме переводчика.
+ * @param {string} path
+ * @param {boolean} removed
+ * @returns {$CancellablePromise<void>}
+ */
+export function ReloadLocale(path, removed) {
+    return $Call.ByID(1278235520, path, removed);
+}
+
 /**
  * SwitchLanguage изменяет текущий язык в настройках приложения и сохраняет их.
+ * Остальные сервисы и фронтенд узнают о смене по обычному уведомлению об изменении настроек.
  * @param {string} newLang
  * @returns {$CancellablePromise<void>}
  */
@@ -61,6 +138,45 @@ export function SwitchLanguage(newLang) {
     return $Call.ByID(3885517577, newLang);
 }
 
+/**
+ * Translate возвращает перевод key на текущем языке с подставленными параметрами params,
+ * с теми же множественными числами и подстановками, что и $t на фронтенде (см. Format).
+ * Если ключа нет, возвращается сам ключ.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} key
+ * @param {{ [_: string]: any }} params
+ * @returns {$CancellablePromise<string>}
+ */
+export function Translate(key, params) {
+    return $Call.ByID(2295037127, key, params);
+}
+
+/**
+ * UseSystemLanguage выбирает язык по системе и сохраняет его в настройках.
+ * Вызывается при первом запуске, пока пользователь не выбрал язык сам.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<string>}
+ */
+export function UseSystemLanguage() {
+    return $Call.ByID(1278603793);
+}
+
+/**
+ * ValidateLocales проверяет встроенные переводы и файлы пользователя по правилам файла перевода.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<$models.LocaleProblem[]>}
+ */
+export function ValidateLocales() {
+    return $Call.ByID(564323192).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType6($result);
+    }));
+}
+
 // Private type creation functions
-const $$createType0 = $Create.Map($Create.Any, $Create.Any);
+const $$createType0 = $models.LocaleReport.createFrom;
 const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = $Create.Map($Create.Any, $Create.Any);
+const $$createType3 = $Create.Array($$createType2);
+const $$createType4 = $models.Translations.createFrom;
+const $$createType5 = $models.LocaleProblem.createFrom;
+const $$createType6 = $Create.Array($$createType5);
@@ -6,3 +6,16 @@ import * as I18N from "./i18n.js";
 export {
     I18N
 };
+
+export {
+    LocaleProblem,
+    LocaleReport,
+    Translations
+} from "./models.js";
+
+import * as $models from "./models.js";
+
+/**
+ * EventEmitter отправляет события фронтенду (реализуется app.Event в Wails).
+ * @typedef {$models.EventEmitter} EventEmitter
+ */
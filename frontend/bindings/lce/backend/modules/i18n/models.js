@@ -0,0 +1,224 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * EventEmitter отправляет события фронтенду (реализуется app.Event в Wails).
+ * @typedef {any} EventEmitter
+ */
+
+/**
+ * LocaleProblem - нарушение правил в файле перевода.
+ */
+export class LocaleProblem {
+    /**
+     * Creates a new LocaleProblem instance.
+     * @param {Partial<LocaleProblem>} [$$source = {}] - The source object to create the LocaleProblem.
+     */
+    constructor($$source = {}) {
+        if (!("file" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["file"] = "";
+        }
+        if (!("key" in $$source)) {
+            /**
+             * полный ключ; пусто - файл целиком
+             * @member
+             * @type {string}
+             */
+            this["key"] = "";
+        }
+        if (!("message" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["message"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LocaleProblem instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {LocaleProblem}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new LocaleProblem(/** @type {Partial<LocaleProblem>} */($$parsedSource));
+    }
+}
+
+/**
+ * LocaleReport - полнота перевода одного языка по сравнению с английским.
+ * Сравниваются полные ключи только файла самого языка, без запасных.
+ */
+export class LocaleReport {
+    /**
+     * Creates a new LocaleReport instance.
+     * @param {Partial<LocaleReport>} [$$source = {}] - The source object to create the LocaleReport.
+     */
+    constructor($$source = {}) {
+        if (!("language" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["language"] = "";
+        }
+        if (!("total" in $$source)) {
+            /**
+             * строк в английском переводе
+             * @member
+             * @type {number}
+             */
+            this["total"] = 0;
+        }
+        if (!("translated" in $$source)) {
+            /**
+             * переведено: есть, не пусто и отличается от английского
+             * @member
+             * @type {number}
+             */
+            this["translated"] = 0;
+        }
+        if (!("missing" in $$source)) {
+            /**
+             * нет в переводе: показывается запасной язык
+             * @member
+             * @type {string[]}
+             */
+            this["missing"] = [];
+        }
+        if (!("extra" in $$source)) {
+            /**
+             * нет в английском: устаревшие ключи или опечатки
+             * @member
+             * @type {string[]}
+             */
+            this["extra"] = [];
+        }
+        if (!("empty" in $$source)) {
+            /**
+             * пустые строки
+             * @member
+             * @type {string[]}
+             */
+            this["empty"] = [];
+        }
+        if (!("untranslated" in $$source)) {
+            /**
+             * совпадают с английскими
+             * @member
+             * @type {string[]}
+             */
+            this["untranslated"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LocaleReport instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {LocaleReport}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType0;
+        const $$createField4_0 = $$createType0;
+        const $$createField5_0 = $$createType0;
+        const $$createField6_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("missing" in $$parsedSource) {
+            $$parsedSource["missing"] = $$createField3_0($$parsedSource["missing"]);
+        }
+        if ("extra" in $$parsedSource) {
+            $$parsedSource["extra"] = $$createField4_0($$parsedSource["extra"]);
+        }
+        if ("empty" in $$parsedSource) {
+            $$parsedSource["empty"] = $$createField5_0($$parsedSource["empty"]);
+        }
+        if ("untranslated" in $$parsedSource) {
+            $$parsedSource["untranslated"] = $$createField6_0($$parsedSource["untranslated"]);
+        }
+        return new LocaleReport(/** @type {Partial<LocaleReport>} */($$parsedSource));
+    }
+}
+
+/**
+ * Translations - переводы языка с указанием строк, взятых из запасных языков.
+ */
+export class Translations {
+    /**
+     * Creates a new Translations instance.
+     * @param {Partial<Translations>} [$$source = {}] - The source object to create the Translations.
+     */
+    constructor($$source = {}) {
+        if (!("language" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["language"] = "";
+        }
+        if (!("chain" in $$source)) {
+            /**
+             * найденные языки цепочки, от запрошенного к английскому
+             * @member
+             * @type {string[]}
+             */
+            this["chain"] = [];
+        }
+        if (!("entries" in $$source)) {
+            /**
+             * @member
+             * @type {{ [_: string]: string }}
+             */
+            this["entries"] = {};
+        }
+        if (!("fallback" in $$source)) {
+            /**
+             * ключ -> язык, из которого взята строка вместо запрошенного
+             * @member
+             * @type {{ [_: string]: string }}
+             */
+            this["fallback"] = {};
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Translations instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Translations}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType0;
+        const $$createField2_0 = $$createType1;
+        const $$createField3_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("chain" in $$parsedSource) {
+            $$parsedSource["chain"] = $$createField1_0($$parsedSource["chain"]);
+        }
+        if ("entries" in $$parsedSource) {
+            $$parsedSource["entries"] = $$createField2_0($$parsedSource["entries"]);
+        }
+        if ("fallback" in $$parsedSource) {
+            $$parsedSource["fallback"] = $$createField3_0($$parsedSource["fallback"]);
+        }
+        return new Translations(/** @type {Partial<Translations>} */($$parsedSource));
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = $Create.Map($Create.Any, $Create.Any);
@@ -0,0 +1,49 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+/**
+ * HealthMonitor периодически проверяет известные установки и следит за подключением дисков.
+ * Эта структура привязана к фронтенду Wails.
+ * @module
+ */
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * CheckNow выполняет проверку немедленно, включая полный поиск новых установок.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<$models.HealthReport>}
+ */
+export function CheckNow() {
+    return $Call.ByID(3533298512).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * StartMonitoring запускает фоновую проверку. Повторный вызов ничего не делает.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<void>}
+ */
+export function StartMonitoring() {
+    return $Call.ByID(14128030);
+}
+
+/**
+ * StopMonitoring останавливает фоновую проверку и ждёт завершения текущей итерации.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<void>}
+ */
+export function StopMonitoring() {
+    return $Call.ByID(2864024978);
+}
+
+// Private type creation functions
+const $$createType0 = $models.HealthReport.createFrom;
@@ -2,7 +2,16 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+import * as HealthMonitor from "./healthmonitor.js";
 import * as Scanner from "./scanner.js";
 export {
+    HealthMonitor,
     Scanner
 };
+
+export {
+    HealthReport,
+    InstallInfo,
+    RelinkSuggestion,
+    ScanRules
+} from "./models.js";
@@ -0,0 +1,318 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../../../../time/models.js";
+
+/**
+ * HealthReport - результат одной проверки.
+ */
+export class HealthReport {
+    /**
+     * Creates a new HealthReport instance.
+     * @param {Partial<HealthReport>} [$$source = {}] - The source object to create the HealthReport.
+     */
+    constructor($$source = {}) {
+        if (!("missing" in $$source)) {
+            /**
+             * известные установки, которых сейчас нет на диске
+             * @member
+             * @type {string[]}
+             */
+            this["missing"] = [];
+        }
+        if (!("found" in $$source)) {
+            /**
+             * новые установки на подключённых дисках
+             * @member
+             * @type {string[]}
+             */
+            this["found"] = [];
+        }
+        if (!("suggestions" in $$source)) {
+            /**
+             * варианты перепривязки пропавших установок
+             * @member
+             * @type {RelinkSuggestion[]}
+             */
+            this["suggestions"] = [];
+        }
+        if (!("checked_at" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time}
+             */
+            this["checked_at"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new HealthReport instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {HealthReport}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType0;
+        const $$createField1_0 = $$createType0;
+        const $$createField2_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("missing" in $$parsedSource) {
+            $$parsedSource["missing"] = $$createField0_0($$parsedSource["missing"]);
+        }
+        if ("found" in $$parsedSource) {
+            $$parsedSource["found"] = $$createField1_0($$parsedSource["found"]);
+        }
+        if ("suggestions" in $$parsedSource) {
+            $$parsedSource["suggestions"] = $$createField2_0($$parsedSource["suggestions"]);
+        }
+        return new HealthReport(/** @type {Partial<HealthReport>} */($$parsedSource));
+    }
+}
+
+/**
+ * InstallInfo описывает найденную папку с игрой.
+ */
+export class InstallInfo {
+    /**
+     * Creates a new InstallInfo instance.
+     * @param {Partial<InstallInfo>} [$$source = {}] - The source object to create the InstallInfo.
+     */
+    constructor($$source = {}) {
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("has_exe" in $$source)) {
+            /**
+             * есть war3.exe
+             * @member
+             * @type {boolean}
+             */
+            this["has_exe"] = false;
+        }
+        if (!("game_version" in $$source)) {
+            /**
+             * версия из ресурса war3.exe, например "1.26.0.6401"
+             * @member
+             * @type {string}
+             */
+            this["game_version"] = "";
+        }
+        if (!("lod_maps" in $$source)) {
+            /**
+             * карты LoD относительно папки игры
+             * @member
+             * @type {string[]}
+             */
+            this["lod_maps"] = [];
+        }
+        if (!("has_loader" in $$source)) {
+            /**
+             * есть файлы загрузчика LoD
+             * @member
+             * @type {boolean}
+             */
+            this["has_loader"] = false;
+        }
+        if (!("has_config" in $$source)) {
+            /**
+             * есть config.lod.ini
+             * @member
+             * @type {boolean}
+             */
+            this["has_config"] = false;
+        }
+        if (!("config_valid" in $$source)) {
+            /**
+             * config.lod.ini успешно разобран
+             * @member
+             * @type {boolean}
+             */
+            this["config_valid"] = false;
+        }
+        if (!("config_error" in $$source)) {
+            /**
+             * ошибка разбора config.lod.ini
+             * @member
+             * @type {string}
+             */
+            this["config_error"] = "";
+        }
+        if (!("writable" in $$source)) {
+            /**
+             * в папку можно записывать
+             * @member
+             * @type {boolean}
+             */
+            this["writable"] = false;
+        }
+        if (!("score" in $$source)) {
+            /**
+             * чем больше, тем вероятнее это рабочая установка
+             * @member
+             * @type {number}
+             */
+            this["score"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new InstallInfo instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {InstallInfo}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("lod_maps" in $$parsedSource) {
+            $$parsedSource["lod_maps"] = $$createField3_0($$parsedSource["lod_maps"]);
+        }
+        return new InstallInfo(/** @type {Partial<InstallInfo>} */($$parsedSource));
+    }
+}
+
+/**
+ * RelinkSuggestion - предложение перепривязать пропавшую установку к новой папке.
+ */
+export class RelinkSuggestion {
+    /**
+     * Creates a new RelinkSuggestion instance.
+     * @param {Partial<RelinkSuggestion>} [$$source = {}] - The source object to create the RelinkSuggestion.
+     */
+    constructor($$source = {}) {
+        if (!("old_path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["old_path"] = "";
+        }
+        if (!("candidates" in $$source)) {
+            /**
+             * наиболее вероятная папка идёт первой
+             * @member
+             * @type {string[]}
+             */
+            this["candidates"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RelinkSuggestion instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {RelinkSuggestion}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("candidates" in $$parsedSource) {
+            $$parsedSource["candidates"] = $$createField1_0($$parsedSource["candidates"]);
+        }
+        return new RelinkSuggestion(/** @type {Partial<RelinkSuggestion>} */($$parsedSource));
+    }
+}
+
+/**
+ * ScanRules - настраиваемые правила поиска папок с игрой.
+ * Хранятся в app_settings и передаются сканеру через RulesProvider.
+ */
+export class ScanRules {
+    /**
+     * Creates a new ScanRules instance.
+     * @param {Partial<ScanRules>} [$$source = {}] - The source object to create the ScanRules.
+     */
+    constructor($$source = {}) {
+        if (!("roots" in $$source)) {
+            /**
+             * корни обхода; пусто - все логические диски
+             * @member
+             * @type {string[]}
+             */
+            this["roots"] = [];
+        }
+        if (!("max_depth" in $$source)) {
+            /**
+             * максимальная глубина от корня
+             * @member
+             * @type {number}
+             */
+            this["max_depth"] = 0;
+        }
+        if (!("excluded_folders" in $$source)) {
+            /**
+             * имена папок, которые не обходятся (без учёта регистра)
+             * @member
+             * @type {string[]}
+             */
+            this["excluded_folders"] = [];
+        }
+        if (!("target_patterns" in $$source)) {
+            /**
+             * шаблоны имён целевых файлов (path.Match, без учёта регистра)
+             * @member
+             * @type {string[]}
+             */
+            this["target_patterns"] = [];
+        }
+        if (!("workers" in $$source)) {
+            /**
+             * размер пула обхода; 0 - по числу ядер
+             * @member
+             * @type {number}
+             */
+            this["workers"] = 0;
+        }
+        if (!("root_timeout_sec" in $$source)) {
+            /**
+             * ограничение времени обхода одного корня; 0 - без ограничения
+             * @member
+             * @type {number}
+             */
+            this["root_timeout_sec"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ScanRules instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ScanRules}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType0;
+        const $$createField2_0 = $$createType0;
+        const $$createField3_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("roots" in $$parsedSource) {
+            $$parsedSource["roots"] = $$createField0_0($$parsedSource["roots"]);
+        }
+        if ("excluded_folders" in $$parsedSource) {
+            $$parsedSource["excluded_folders"] = $$createField2_0($$parsedSource["excluded_folders"]);
+        }
+        if ("target_patterns" in $$parsedSource) {
+            $$parsedSource["target_patterns"] = $$createField3_0($$parsedSource["target_patterns"]);
+        }
+        return new ScanRules(/** @type {Partial<ScanRules>} */($$parsedSource));
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = RelinkSuggestion.createFrom;
+const $$createType2 = $Create.Array($$createType1);
@@ -12,27 +12,81 @@
 // @ts-ignore: Unused imports
 import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * CancelScan прерывает текущее сканирование. Уже найденные пути будут возвращены.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<void>}
+ */
+export function CancelScan() {
+    return $Call.ByID(1062619865);
+}
+
+/**
+ * CheckAndFindInstalls ищет пути так же, как CheckAndFindPaths, и описывает каждую найденную папку.
+ * Эта функция привязана к фронтенду Wails.
+ * @returns {$CancellablePromise<$models.InstallInfo[]>}
+ */
+export function CheckAndFindInstalls() {
+    return $Call.ByID(2723181280).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType1($result);
+    }));
+}
+
 /**
  * CheckAndFindPaths - основная функция для поиска путей.
+ * Сканирование можно прервать через CancelScan или отменив вызов на фронтенде,
+ * в этом случае возвращаются пути, найденные до отмены.
  * Эта функция будет привязана к фронтенду Wails.
  * @returns {$CancellablePromise<string[]>}
  */
 export function CheckAndFindPaths() {
     return $Call.ByID(692361388).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType2($result);
+    }));
+}
+
+/**
+ * DescribeInstall возвращает сведения об одной папке с игрой.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string} dir
+ * @returns {$CancellablePromise<$models.InstallInfo>}
+ */
+export function DescribeInstall(dir) {
+    return $Call.ByID(2950396450, dir).then(/** @type {($result: any) => any} */(($result) => {
         return $$createType0($result);
     }));
 }
 
+/**
+ * DescribeInstalls возвращает сведения о переданных папках, отсортированные
+ * так, что наиболее подходящая установка идёт первой.
+ * Эта функция привязана к фронтенду Wails.
+ * @param {string[]} paths
+ * @returns {$CancellablePromise<$models.InstallInfo[]>}
+ */
+export function DescribeInstalls(paths) {
+    return $Call.ByID(662801795, paths).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType1($result);
+    }));
+}
+
 /**
  * FindConfigOrExeParallel параллельно ищет пути к файлам config.lod.ini или war3.exe на всех логических дисках.
+ * Во время поиска отправляются события scan-progress и scan-path-found.
  * Эта функция привязана к фронтенду Wails.
  * @returns {$CancellablePromise<string[]>}
  */
 export function FindConfigOrExeParallel() {
     return $Call.ByID(2040538117).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType0($result);
+        return $$createType2($result);
     }));
 }
 
 // Private type creation functions
-const $$createType0 = $Create.Array($Create.Any);
+const $$createType0 = $models.InstallInfo.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = $Create.Array($Create.Any);
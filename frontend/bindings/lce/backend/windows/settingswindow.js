@@ -7,7 +7,7 @@
 import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
 
 /**
- * Close скрывает окно
+ * Close скрывает окно, запомнив его положение
  * @returns {$CancellablePromise<void>}
  */
 export function Close() {
@@ -1,3 +1,4 @@
+import { Events } from "@wailsio/runtime";
 import { LoadTheme } from "../../bindings/lce/backend/modules/theming/themeservice";
 
 export async function applyTheme(name) {
@@ -13,3 +14,21 @@ export async function applyTheme(name) {
     console.error("Ошибка загрузки темы:", e);
   }
 }
+
+// Перезагружает тему, если её файл изменили на диске.
+// getCurrent возвращает имя текущей темы.
+export function watchThemeChanges(getCurrent) {
+  const listener = (event) => {
+    // Данные события: { target, path, removed, ... }
+    const change = Array.isArray(event.data) ? event.data[0] : event.data;
+    if (!change || change.removed) return;
+
+    const name = change.path.split(/[\\/]/).pop().replace(/\.json$/, "");
+    if (name === getCurrent()) {
+      console.log("🎨 Theme file changed, reloading:", name);
+      applyTheme(name);
+    }
+  };
+
+  return { off: Events.On("theme-changed", listener) };
+}
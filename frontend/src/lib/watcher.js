@@ -4,33 +4,28 @@ import {
   StartWatching,
   StopWatching,
 } from "../../bindings/lce/backend/modules/config_watcher/configwatcher";
-import { get } from "svelte/store";
-import { isInternalChange } from "../store/internalChange";
 
 let currentPath = null;
 
 // --- API для UI --- //
 export function onConfigChanged(callback) {
   const listener = (event) => {
-    const filePath = Array.isArray(event.data) ? event.data[0] : event.data;
+    // Данные события: { target, path, hash, internal, generation }
+    const change = Array.isArray(event.data) ? event.data[0] : event.data;
+    if (!change) return;
 
-    // Игнорируем изменения, сделанные программой
-    if (get(isInternalChange)) {
-      console.log("⚡ Internal config changed:", filePath);
+    // Изменения, записанные самой программой, бэкенд помечает как internal
+    if (change.internal) {
+      console.log("💾 Internal config changed:", change.path, change.generation);
       return;
     }
 
     // Внешние изменения
-    console.log("⚡ External config changed:", filePath);
-    // Events.Emit("external-config-changed", filePath); // для бэка
-    callback(filePath);
+    console.log("⚡ External config changed:", change.path);
+    callback(change.path, change);
   };
 
-  Events.On("config-changed", listener);
-
-  return {
-    off: () => Events.Off("config-changed", listener),
-  };
+  return { off: Events.On("config-changed", listener) };
 }
 
 export async function startWatcher(path) {
@@ -2,9 +2,17 @@
 import "./lib/icons";
 import "./lib/tooltip";
 import App from "./App.svelte";
-import { initGoI18n } from "./store/i18n";
-import { loadSettings, appSettings } from "./store/appSettings";
-import { applyTheme } from "./lib/theming";
+import {
+  initGoI18n,
+  watchLocaleChanges,
+  watchLocaleReloads,
+} from "./store/i18n";
+import {
+  loadSettings,
+  appSettings,
+  watchSettingsChanges,
+} from "./store/appSettings";
+import { applyTheme, watchThemeChanges } from "./lib/theming";
 import { get } from "svelte/store";
 
 async function initialiseApp() {
@@ -15,6 +23,14 @@ async function initialiseApp() {
     applyTheme(theme);
   }); // Загружаем настройки
 
+  // Изменения настроек из Go (смена языка, другие окна, правка settings.json)
+  watchSettingsChanges();
+
+  // Горячая перезагрузка тем и переводов при правке их файлов
+  watchThemeChanges(() => get(appSettings).theme);
+  watchLocaleChanges();
+  watchLocaleReloads();
+
   const app = new App({
     target: document.getElementById("app"),
   });
@@ -5,9 +5,15 @@ import {
   GetSettings,
   UpdateSettings,
   GetOption,
+  RemoveInstallPath,
 } from "../../bindings/lce/backend/modules/app_settings/appsettings";
 
-import { CheckAndFindPaths } from "../../bindings/lce/backend/modules/paths_scanner/scanner";
+import { Events } from "@wailsio/runtime";
+import {
+  CheckAndFindPaths,
+  CancelScan,
+  DescribeInstalls,
+} from "../../bindings/lce/backend/modules/paths_scanner/scanner";
 
 // --- Store и базовые методы --- //
 
@@ -43,6 +49,17 @@ export async function loadSettings() {
   }
 }
 
+// Держит store в курсе изменений, сделанных в Go: смена языка, другое окно,
+// правка settings.json вручную. Настройки приходят в событии "app-settings-updated".
+export function watchSettingsChanges() {
+  const listener = (event) => {
+    const settings = Array.isArray(event.data) ? event.data[0] : event.data;
+    if (settings) appSettings.set(settings);
+  };
+
+  return { off: Events.On("app-settings-updated", listener) };
+}
+
 export async function updateSettings(newSettings) {
   try {
     appSettings.update((current) => ({ ...current, ...newSettings }));
@@ -120,16 +137,64 @@ export async function updateTheme(theme) {
 
 // --- Управление путями (NEW) --- //
 
+// Состояние текущего сканирования для оверлея: null, пока сканер не запущен.
+// Обновляется событиями scan-started, scan-progress и scan-path-found из Go
+export const scanProgress = writable(null);
+
+// Сведения о папках из последнего сканирования (версия игры, наличие config.lod.ini) по пути
+export const scannedInstalls = writable({});
+
+// Данные события Wails: Go передаёт их одним аргументом, runtime может завернуть его в массив
+function eventData(event) {
+  return Array.isArray(event.data) ? event.data[0] : event.data;
+}
+
+function watchScanEvents() {
+  const offs = [
+    Events.On("scan-started", (event) => {
+      const roots = eventData(event) || [];
+      scanProgress.set({
+        current_root: "",
+        roots_done: 0,
+        roots_total: roots.length,
+        dirs_visited: 0,
+        candidates_found: 0,
+      });
+    }),
+    Events.On("scan-progress", (event) => {
+      const progress = eventData(event);
+      if (progress) scanProgress.set(progress);
+    }),
+    Events.On("scan-path-found", () => {
+      scanProgress.update((p) =>
+        p ? { ...p, candidates_found: p.candidates_found + 1 } : p,
+      );
+    }),
+  ];
+  return () => offs.forEach((off) => off());
+}
+
 // Сканирование системы на предмет путей
 export async function runScanner() {
+  const stopWatching = watchScanEvents();
   try {
     const pathsFound = await CheckAndFindPaths();
     console.log("Найденные пути:", pathsFound);
 
+    // Найденные пути добавляются к известным: установки, которых сканирование
+    // не нашло (или не успело найти), остаются в списке
     await updateAllPaths(pathsFound);
 
-    if (pathsFound.length === 1) {
-      await updateGamePath(pathsFound[0]);
+    // DescribeInstalls возвращает папки, отсортированные по тому, насколько они похожи
+    // на рабочую установку. Если путь ещё не выбран, выбирается лучшая из найденных
+    const installs = pathsFound.length > 0 ? await DescribeInstalls(pathsFound) : [];
+    scannedInstalls.update((known) => {
+      const next = { ...known };
+      for (const info of installs) next[info.path] = info;
+      return next;
+    });
+    if (installs.length > 0 && !get(appSettings).game_path) {
+      await updateGamePath(installs[0].path);
     }
 
     const isFirstRun = get(appSettings).first_run;
@@ -141,17 +206,29 @@ export async function runScanner() {
   } catch (error) {
     console.error("Ошибка при сканировании путей:", error);
     return [];
+  } finally {
+    stopWatching();
+    scanProgress.set(null);
   }
 }
 
-// Удаление конкретного пути
-export async function deletePath(pathToDelete) {
-  const current = get(appSettings);
-  const updatedPaths = current.all_paths.filter((p) => p !== pathToDelete);
-
-  await updateAllPaths(updatedPaths);
+// Прерывает сканирование: runScanner получит пути, найденные до отмены, и сохранит их
+export async function cancelScanner() {
+  try {
+    await CancelScan();
+  } catch (error) {
+    console.error("Ошибка отмены сканирования:", error);
+  }
+}
 
-  if (pathToDelete === current.game_path) {
-    await updateGamePath(updatedPaths[0] || "");
+// Удаление конкретного пути. all_paths установки только добавляет (туда же пишет
+// результат сканирования), поэтому удаление - отдельный вызов. Если путь был выбран,
+// Go сам выберет другую установку и пришлёт обновлённые настройки
+export async function deletePath(pathToDelete) {
+  try {
+    await RemoveInstallPath(pathToDelete);
+  } catch (error) {
+    console.error("Ошибка удаления пути:", error);
   }
+  await loadSettings();
 }
@@ -1,5 +1,7 @@
 //@ts-nocheck
 
+import { Events } from "@wailsio/runtime";
+import { get } from "svelte/store";
 import { addMessages, init, locale } from "svelte-i18n";
 import {
   GetTranslations,
@@ -31,3 +33,39 @@ export async function initGoI18n() {
   });
   await loadGoTranslations(lang);
 }
+
+// Перезагружает переводы текущего языка, если его файл изменили на диске.
+export function watchLocaleChanges() {
+  const listener = async (event) => {
+    // Данные события: { target, path, removed, ... }
+    const change = Array.isArray(event.data) ? event.data[0] : event.data;
+    if (!change || change.removed) return;
+
+    const lang = change.path.split(/[\\/]/).pop().replace(/\.json$/, "");
+    if (lang === get(locale)) {
+      console.log("🌐 Locale file changed, reloading:", lang);
+      await loadGoTranslations(lang);
+    }
+  };
+
+  return { off: Events.On("locale-changed", listener) };
+}
+
+// Режим переводчика: Go перечитывает изменённый файл перевода, проверяет его
+// и присылает новые строки текущего языка вместе с найденными ошибками.
+export function watchLocaleReloads() {
+  return Events.On("locale-reloaded", (event) => {
+    // Данные события: { file, language, problems, translations }
+    const reload = Array.isArray(event.data) ? event.data[0] : event.data;
+    if (!reload) return;
+
+    for (const problem of reload.problems ?? []) {
+      const where = problem.key ? `${problem.file}: ${problem.key}` : problem.file;
+      console.warn(`🌐 ${where}: ${problem.message}`);
+    }
+    if (reload.translations && reload.language === get(locale)) {
+      console.log("🌐 Translations reloaded:", reload.file);
+      addMessages(reload.language, reload.translations.entries);
+    }
+  });
+}
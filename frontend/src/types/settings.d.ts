@@ -0,0 +1,52 @@
+// Этот файл создан автоматически из реестра настроек (backend/modules/app_settings/fields.go).
+// Не редактируйте вручную: запустите task common:generate:settings-types.
+
+import type { Install, WindowState } from "../../bindings/lce/backend/modules/app_settings/models";
+import type { ScanRules } from "../../bindings/lce/backend/modules/paths_scanner/models";
+
+export interface Settings {
+  /** только для чтения */
+  readonly version: number;
+  /** применяется после перезапуска */
+  width: number;
+  /** применяется после перезапуска */
+  height: number;
+  language: string;
+  first_run: boolean;
+  all_paths: string[];
+  game_path: string;
+  theme: string;
+  scan_rules: ScanRules;
+  /** только для чтения */
+  readonly installs: Install[];
+  /** только для чтения */
+  readonly active_install_id: string;
+  watch_mode: "auto" | "native" | "poll";
+  watch_poll_interval_ms: number;
+  /** только для чтения */
+  readonly windows: Record<string, WindowState>;
+}
+
+/** Поля, которые можно передать в UpdateSettings. */
+export type SettingsUpdate = Partial<Pick<Settings,
+  "width" |
+  "height" |
+  "language" |
+  "first_run" |
+  "all_paths" |
+  "game_path" |
+  "theme" |
+  "scan_rules" |
+  "watch_mode" |
+  "watch_poll_interval_ms"
+>>;
+
+export type SettingsKey = keyof Settings;
+
+/** Настройки, вступающие в силу после перезапуска. */
+export type RestartRequiredSetting = "width" | "height";
+
+export interface FieldError {
+  field: string;
+  message: string;
+}
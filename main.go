@@ -2,13 +2,19 @@ package main
 
 import (
 	"embed"
+	"io"
+	"io/fs"
 	"log"
+	"os"
+	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
 
 	"lce/backend/modules/app_settings"
 	"lce/backend/modules/config_editor"
 	"lce/backend/modules/config_watcher"
+	"lce/backend/modules/data_dir"
 	"lce/backend/modules/i18n"
 	"lce/backend/modules/paths_scanner"
 	"lce/backend/modules/theming"
@@ -18,42 +24,220 @@ import (
 //go:embed all:frontend/dist
 var assets embed.FS
 
+// Переводы, поставляемые с программой; пользовательские лежат в папке данных (data_dir.Locales)
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
 func main() {
+	// Расположение файлов выбирается до всего остального: от него зависят настройки, темы и переводы
+	layout, err := data_dir.Setup(os.Args[1:])
+	if err != nil {
+		log.Println("Не удалось определить папку данных:", err)
+	}
+	setupLogFile()
+	log.Println("Файлы приложения:", layout)
+
+	// Общие настройки: сервисы читают их и подписываются на изменения через это хранилище
+	settingsStore := app_settings.NewStore()
+
+	// Журнал сохранений редактора: по нему вотчер отличает свои записи config.lod.ini от внешних
+	configWrites := config_watcher.NewWriteLog()
+	configEditor := config_editor.NewConfigEditor(settingsStore, configWrites)
+
+	shippedLocales, err := fs.Sub(localeFiles, "locales")
+	if err != nil {
+		log.Fatal("Не удалось открыть встроенные переводы:", err)
+	}
+	// В режиме переводчика файлы locales читаются с диска и перезагружаются при сохранении
+	translatorDir, translatorMode := i18n.TranslatorDir(os.Args[1:])
+	if translatorMode {
+		log.Println("Режим переводчика: переводы читаются из", translatorDir)
+		shippedLocales = os.DirFS(translatorDir)
+	}
+	translations := i18n.NewI18N(settingsStore, shippedLocales)
+	// Ошибки в файлах переводов не мешают запуску: строки с ними показываются как есть
+	for _, problem := range translations.ValidateLocales() {
+		log.Println("Ошибка в переводе:", problem)
+	}
+	// При первом запуске язык берётся из системы; дальше его меняет только пользователь
+	if settingsStore.FirstLaunch() {
+		if _, err := translations.UseSystemLanguage(); err != nil {
+			log.Println("Не удалось выбрать язык по системе:", err)
+		}
+	}
 
 	app := application.New(application.Options{
 		Name:        "LoD Config Editor",
 		Description: "A demo of using raw HTML & CSS",
 		Services: []application.Service{
-			application.NewService(i18n.NewI18N()),
+			application.NewService(translations),
 			application.NewService(theming.NewThemeService()),
-			application.NewService(paths_scanner.NewScanner()),
-			application.NewService(config_editor.NewConfigEditor()),
+			application.NewService(configEditor),
 		},
 		Assets: application.AssetOptions{
 			Handler: application.AssetFileServerFS(assets),
 		},
 	})
 
+	// Размер главного окна берётся из настроек; положение и развёрнутость восстанавливаются
+	// после запуска, когда известны подключённые мониторы
+	mainWidth, mainHeight := 1300, 800
+	if state, ok := settingsStore.Get().Window(app_settings.MainWindow); ok {
+		mainWidth, mainHeight = state.Width, state.Height
+	}
 	mainWindow := app.Window.NewWithOptions(application.WebviewWindowOptions{
 		Title:     "LoD Config Editor",
-		Width:     1300,
-		Height:    800,
+		Width:     mainWidth,
+		Height:    mainHeight,
 		Frameless: true,
 		URL:       "/",
 	})
+	mainGeometry := windows.TrackGeometry(app, mainWindow, app_settings.MainWindow, settingsStore)
+	app.Event.OnApplicationEvent(events.Common.ApplicationStarted, func(*application.ApplicationEvent) {
+		mainGeometry.Restore()
+	})
 
-	appSettings := app_settings.NewAppSettings(app)
+	settingsStore.Publish(app.Event)
+	appSettings := app_settings.NewAppSettings(settingsStore)
 	app.RegisterService(application.NewService(appSettings))
 
-	settingsWindow := windows.NewSettingsWindow(app, mainWindow)
+	var scanCache *paths_scanner.ScanCache
+	if cachePath, err := data_dir.File(data_dir.Data, "scan_cache.json"); err == nil {
+		scanCache = paths_scanner.LoadScanCache(cachePath)
+	} else {
+		log.Printf("Кэш сканирования отключён: %v", err)
+	}
+	scanner := paths_scanner.NewScanner(app.Event, appSettings, scanCache)
+	app.RegisterService(application.NewService(scanner))
+
+	// Фоновая проверка известных установок и поиск игры на подключаемых дисках
+	healthMonitor := paths_scanner.NewHealthMonitor(scanner, appSettings, 0)
+	app.RegisterService(application.NewService(healthMonitor))
+	healthMonitor.StartMonitoring()
+
+	settingsWindow := windows.NewSettingsWindow(app, mainWindow, settingsStore)
 	app.RegisterService(application.NewService(settingsWindow))
 
-	configWatcher := config_watcher.New(app)
+	// Наблюдение за файлами: конфиг игры, темы, переводы и settings.json
+	watchHub := config_watcher.NewHub(app.Event, configWrites)
+	configWatcher := config_watcher.New(watchHub)
 	app.RegisterService(application.NewService(configWatcher))
+	applyWatchMode(watchHub, appSettings.GetSettings())
+	translations.Publish(app.Event)
+	watchAppFiles(watchHub, appSettings, translations, translatorDir)
 
-	err := app.Run()
+	settingsStore.Subscribe(func(previous, current app_settings.Settings) {
+		// Режим наблюдения (уведомления ОС или опрос) меняется в настройках без перезапуска
+		if previous.WatchMode != current.WatchMode || previous.WatchPollIntervalMs != current.WatchPollIntervalMs {
+			applyWatchMode(watchHub, current)
+		}
+		// При смене активной установки редактор и вотчер переходят на её config.lod.ini
+		if previous.ActiveInstallID != current.ActiveInstallID {
+			switchInstall(configEditor, configWatcher, current)
+		}
+	})
+
+	err = app.Run()
+	watchHub.Close()
 
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// maxLogSize - размер журнала, после которого при запуске начинается новый файл.
+const maxLogSize = 1 << 20
+
+// setupLogFile дублирует журнал в файл lce.log в папке журналов (в переносном режиме - рядом с программой).
+// Предыдущий большой журнал сохраняется как lce.log.old.
+func setupLogFile() {
+	logPath, err := data_dir.File(data_dir.Logs, "lce.log")
+	if err != nil {
+		log.Println("Журнал в файл не пишется:", err)
+		return
+	}
+	if info, err := os.Stat(logPath); err == nil && info.Size() > maxLogSize {
+		os.Rename(logPath, logPath+".old")
+	}
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		log.Println("Журнал в файл не пишется:", err)
+		return
+	}
+	// Файл первым: у приложения без консоли запись в stderr может завершаться ошибкой
+	log.SetOutput(io.MultiWriter(f, os.Stderr))
+}
+
+// applyWatchMode применяет к хабу способ наблюдения из настроек.
+func applyWatchMode(hub *config_watcher.Hub, settings app_settings.Settings) {
+	interval := time.Duration(settings.WatchPollIntervalMs) * time.Millisecond
+	if err := hub.SetMode(settings.WatchMode, interval); err != nil {
+		log.Println("Не удалось применить режим наблюдения:", err)
+	}
+}
+
+// switchInstall переключает редактор и вотчер на config.lod.ini активной установки.
+// Если активной установки не осталось, оба отвязываются от конфига предыдущей.
+func switchInstall(editor *config_editor.ConfigEditor, watcher *config_watcher.ConfigWatcher, settings app_settings.Settings) {
+	install, ok := settings.ActiveInstall()
+	if !ok || install.Path == "" {
+		if err := editor.SwitchInstall(""); err != nil {
+			log.Println(err)
+		}
+		watcher.StopWatching()
+		return
+	}
+	if err := editor.SwitchInstall(install.Path); err != nil {
+		log.Println(err)
+	}
+	if err := watcher.SwitchInstall(install.ConfigPath(), install.WatchEnabled); err != nil {
+		log.Println("Не удалось переключить вотчер:", err)
+	}
+}
+
+// watchAppFiles подписывается на изменения тем, переводов и settings.json.
+// Темы и переводы фронтенд перезагружает по событиям, settings.json перечитывается в Go.
+// В режиме переводчика (translatorDir не пуст) переводы перезагружает и проверяет i18n.
+func watchAppFiles(hub *config_watcher.Hub, appSettings *app_settings.AppSettings, translations *i18n.I18N, translatorDir string) {
+	var targets []config_watcher.Target
+	if translatorDir == "" {
+		targets = append(targets, config_watcher.Target{
+			Name: config_watcher.TargetLocales, Path: i18n.LocalesDir(), Dir: true, Pattern: "*.json", Event: config_watcher.EventLocaleChanged,
+			// Кэш сбрасывается до события, чтобы фронтенд получил уже новые строки
+			OnChange: func(config_watcher.FileChange) { translations.ClearCache() },
+		})
+	} else {
+		reload := func(change config_watcher.FileChange) { translations.ReloadLocale(change.Path, change.Removed) }
+		targets = append(targets,
+			config_watcher.Target{Name: config_watcher.TargetLocales, Path: i18n.LocalesDir(), Dir: true, Pattern: "*.json", OnChange: reload},
+			config_watcher.Target{Name: config_watcher.TargetLocaleSources, Path: translatorDir, Dir: true, Pattern: "*.json", OnChange: reload},
+		)
+	}
+	if themesDir, err := theming.ThemesDir(); err == nil {
+		targets = append(targets, config_watcher.Target{
+			Name: config_watcher.TargetThemes, Path: themesDir, Dir: true, Pattern: "*.json", Event: config_watcher.EventThemeChanged,
+		})
+	} else {
+		log.Println(err)
+	}
+	if settingsPath, err := app_settings.SettingsPath(); err == nil {
+		targets = append(targets, config_watcher.Target{
+			Name: config_watcher.TargetSettings,
+			Path: settingsPath,
+			OnChange: func(config_watcher.FileChange) {
+				if _, err := appSettings.ReloadSettings(); err != nil {
+					log.Println(err)
+				}
+			},
+		})
+	} else {
+		log.Println(err)
+	}
+
+	for _, target := range targets {
+		if err := hub.Watch(target); err != nil {
+			log.Printf("Не удалось начать наблюдение за %s: %v", target.Name, err)
+		}
+	}
+}